@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"syscall"
 
 	"github.com/docker/docker/api/server/httputils"
@@ -107,9 +108,12 @@ func (s *containerRouter) getContainersStats(ctx context.Context, w http.Respons
 	}
 
 	config := &backend.ContainerStatsConfig{
-		Stream:    stream,
-		OutStream: w,
-		Version:   httputils.VersionFromContext(ctx),
+		Stream:     stream,
+		OutStream:  w,
+		Version:    httputils.VersionFromContext(ctx),
+		Since:      r.Form.Get("since"),
+		Until:      r.Form.Get("until"),
+		Resolution: r.Form.Get("resolution"),
 	}
 
 	return s.backend.ContainerStats(ctx, vars["name"], config)
@@ -140,6 +144,21 @@ func (s *containerRouter) getContainersLogs(ctx context.Context, w http.Response
 		ShowStdout: stdout,
 		ShowStderr: stderr,
 		Details:    httputils.BoolValue(r, "details"),
+		Include:    r.Form["include"],
+		Exclude:    r.Form["exclude"],
+	}
+	for _, attr := range r.Form["attrs"] {
+		k, v := attr, ""
+		if i := strings.Index(attr, "="); i != -1 {
+			k, v = attr[:i], attr[i+1:]
+		}
+		if k == "" {
+			return errdefs.InvalidParameter(errors.Errorf("invalid attrs filter %q: must be key=value", attr))
+		}
+		if logsConfig.Attrs == nil {
+			logsConfig.Attrs = make(map[string]string)
+		}
+		logsConfig.Attrs[k] = v
 	}
 
 	msgs, tty, err := s.backend.ContainerLogs(ctx, containerName, logsConfig)
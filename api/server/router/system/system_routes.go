@@ -10,6 +10,7 @@ import (
 	"github.com/docker/docker/api/server/httputils"
 	"github.com/docker/docker/api/server/router/build"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/registry"
@@ -135,6 +136,27 @@ func (s *systemRouter) getDiskUsage(ctx context.Context, w http.ResponseWriter,
 	return httputils.WriteJSON(w, http.StatusOK, du)
 }
 
+func (s *systemRouter) getStats(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	sf, err := filters.FromJSON(r.Form.Get("filters"))
+	if err != nil {
+		return err
+	}
+
+	stream := httputils.BoolValueOrDefault(r, "stream", true)
+	if !stream {
+		w.Header().Set("Content-Type", "application/json")
+	}
+
+	return s.backend.SystemStats(ctx, &backend.SystemStatsConfig{
+		Stream:    stream,
+		OutStream: w,
+		Filters:   sf,
+	})
+}
+
 type invalidRequestError struct {
 	Err error
 }
@@ -184,6 +206,11 @@ func (s *systemRouter) getEvents(ctx context.Context, w http.ResponseWriter, r *
 	if err != nil {
 		return err
 	}
+	for _, key := range []string{"attr", "attr!"} {
+		if err := ef.ValidateAttrs(key); err != nil {
+			return err
+		}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	output := ioutils.NewWriteFlusher(w)
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 )
 
 // ContainerAttachConfig holds the streams to use when connecting to a container to view logs.
@@ -75,6 +76,23 @@ type ContainerStatsConfig struct {
 	Stream    bool
 	OutStream io.Writer
 	Version   string
+
+	// Since, Until and Resolution select the samples of the stats history
+	// to return instead of the live stats.
+	Since      string
+	Until      string
+	Resolution string
+}
+
+// SystemStatsConfig holds information for configuring the runtime
+// behavior of a backend.SystemStats() call.
+type SystemStatsConfig struct {
+	Stream    bool
+	OutStream io.Writer
+
+	// Filters selects the containers, like the filters of the container
+	// list.
+	Filters filters.Args
 }
 
 // ExecInspect holds information about a running process started
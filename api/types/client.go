@@ -79,6 +79,30 @@ type ContainerLogsOptions struct {
 	Follow     bool
 	Tail       string
 	Details    bool
+
+	// Include and Exclude are regular expressions the log lines must match,
+	// and must not match, respectively.
+	Include []string
+	Exclude []string
+	// Attrs are the attributes the log messages must have.
+	Attrs map[string]string
+}
+
+// ContainerStatsHistoryOptions holds parameters to get the stats history of
+// a container.
+type ContainerStatsHistoryOptions struct {
+	Since string
+	Until string
+	// Resolution is the minimum interval between two samples. The finest
+	// resolution of the history is used if it's not set.
+	Resolution string
+}
+
+// SystemStatsOptions holds parameters to get the stats of the running
+// containers of the host.
+type SystemStatsOptions struct {
+	Stream  bool
+	Filters filters.Args
 }
 
 // ContainerRemoveOptions holds parameters to remove containers.
@@ -249,6 +273,13 @@ type ImageLoadResponse struct {
 	JSON bool
 }
 
+// ImageSaveOptions holds parameters to save images.
+type ImageSaveOptions struct {
+	// Format is the format of the archive, either "docker" (the default) or
+	// "oci" for the OCI image layout.
+	Format string
+}
+
 // ImagePullOptions holds information to pull images.
 type ImagePullOptions struct {
 	All           bool
@@ -2,6 +2,7 @@ package container // import "github.com/docker/docker/api/types/container"
 
 import (
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types/blkiodev"
 	"github.com/docker/docker/api/types/mount"
@@ -288,6 +289,23 @@ type DeviceMapping struct {
 type RestartPolicy struct {
 	Name              string
 	MaximumRetryCount int
+
+	// Backoff settings applied between restarts. Zero means use the
+	// daemon's default.
+	InitialDelay time.Duration `json:",omitempty"` // Delay before the first restart
+	MaxDelay     time.Duration `json:",omitempty"` // Upper bound for the delay between restarts
+	Multiplier   float64       `json:",omitempty"` // Factor applied to the delay after each restart
+	ResetWindow  time.Duration `json:",omitempty"` // Run time after which the delay is reset to InitialDelay
+
+	// Crash-loop detection. A container is reported as crash-looping
+	// once it has been restarted CrashLoopThreshold times within
+	// CrashLoopWindow. Zero means use the daemon's default.
+	CrashLoopThreshold int           `json:",omitempty"`
+	CrashLoopWindow    time.Duration `json:",omitempty"`
+
+	// UnhealthyTimeout is how long a container with the "on-unhealthy"
+	// restart policy has to stay unhealthy before it is restarted.
+	UnhealthyTimeout time.Duration `json:",omitempty"`
 }
 
 // IsNone indicates whether the container has the "no" restart policy.
@@ -308,6 +326,13 @@ func (rp *RestartPolicy) IsOnFailure() bool {
 	return rp.Name == "on-failure"
 }
 
+// IsOnUnhealthy indicates whether the container has the "on-unhealthy" restart policy.
+// This means the container will automatically restart if exiting with a non-zero exit
+// status, or after its health check reported it as unhealthy for UnhealthyTimeout.
+func (rp *RestartPolicy) IsOnUnhealthy() bool {
+	return rp.Name == "on-unhealthy"
+}
+
 // IsUnlessStopped indicates whether the container has the
 // "unless-stopped" restart policy. This means the container will
 // automatically restart unless user has put it to stopped state.
@@ -317,7 +342,7 @@ func (rp *RestartPolicy) IsUnlessStopped() bool {
 
 // IsSame compares two RestartPolicy to see if they are the same
 func (rp *RestartPolicy) IsSame(tp *RestartPolicy) bool {
-	return rp.Name == tp.Name && rp.MaximumRetryCount == tp.MaximumRetryCount
+	return *rp == *tp
 }
 
 // LogMode is a type to define the available modes for logging
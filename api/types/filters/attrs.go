@@ -0,0 +1,119 @@
+package filters // import "github.com/docker/docker/api/types/filters"
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+// attrOperators are the operators of attribute expressions. Two-character
+// operators come first so that "!=" is not parsed as "!" followed by "=".
+var attrOperators = []string{"!=", ">=", "<=", "=", ">", "<"}
+
+// attrExpr is an attribute expression, such as "exitCode>0", "signal=9",
+// or "image!=busybox*". An expression without operator matches if the
+// attribute is set.
+type attrExpr struct {
+	key   string
+	op    string
+	value string
+	num   float64 // value of numeric comparisons
+}
+
+func parseAttrExpr(expr string) (attrExpr, error) {
+	i := strings.IndexAny(expr, "!=<>")
+	if i == -1 {
+		return attrExpr{key: expr}, nil
+	}
+	if i == 0 {
+		return attrExpr{}, invalidFilter(expr)
+	}
+	rest := expr[i:]
+	for _, op := range attrOperators {
+		if !strings.HasPrefix(rest, op) {
+			continue
+		}
+		e := attrExpr{key: expr[:i], op: op, value: rest[len(op):]}
+		switch op {
+		case "=", "!=":
+			if _, err := path.Match(e.value, ""); err != nil {
+				return attrExpr{}, invalidFilter(expr)
+			}
+		default:
+			n, err := strconv.ParseFloat(e.value, 64)
+			if err != nil {
+				return attrExpr{}, invalidFilter(expr)
+			}
+			e.num = n
+		}
+		return e, nil
+	}
+	return attrExpr{}, invalidFilter(expr)
+}
+
+func (e attrExpr) match(attrs map[string]string) bool {
+	v, ok := attrs[e.key]
+	switch e.op {
+	case "":
+		return ok
+	case "=":
+		return ok && globMatch(e.value, v)
+	case "!=":
+		return !ok || !globMatch(e.value, v)
+	}
+	if !ok {
+		return false
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return false
+	}
+	switch e.op {
+	case ">":
+		return n > e.num
+	case ">=":
+		return n >= e.num
+	case "<":
+		return n < e.num
+	default: // "<="
+		return n <= e.num
+	}
+}
+
+func globMatch(pattern, s string) bool {
+	if pattern == s {
+		return true
+	}
+	match, err := path.Match(pattern, s)
+	return err == nil && match
+}
+
+// MatchAttrs returns true if all the attribute expressions at key hold for
+// attrs, or if there are no values at key.
+//
+// Expressions are of the form "<name><op><value>", where op is one of "="
+// or "!=", in which case value can be a shell pattern (see path.Match), or
+// one of ">", ">=", "<", or "<=", in which case both the value and the
+// attribute are compared as numbers. Combining expressions allows to match
+// ranges, e.g. "exitCode>=1" and "exitCode<=127". An expression consisting
+// of only a name matches if the attribute is set.
+func (args Args) MatchAttrs(key string, attrs map[string]string) bool {
+	for expr := range args.fields[key] {
+		e, err := parseAttrExpr(expr)
+		if err != nil || !e.match(attrs) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateAttrs returns an error if any of the values at key is not a valid
+// attribute expression.
+func (args Args) ValidateAttrs(key string) error {
+	for expr := range args.fields[key] {
+		if _, err := parseAttrExpr(expr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package filters // import "github.com/docker/docker/api/types/filters"
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestMatchAttrs(t *testing.T) {
+	attrs := map[string]string{
+		"exitCode": "137",
+		"signal":   "9",
+		"image":    "busybox:latest",
+	}
+
+	cases := []struct {
+		exprs []string
+		match bool
+	}{
+		{exprs: nil, match: true},
+		{exprs: []string{"exitCode"}, match: true},
+		{exprs: []string{"oomKilled"}, match: false},
+		{exprs: []string{"signal=9"}, match: true},
+		{exprs: []string{"signal=15"}, match: false},
+		{exprs: []string{"signal!=15"}, match: true},
+		{exprs: []string{"signal!=9"}, match: false},
+		{exprs: []string{"oomKilled!=true"}, match: true},
+		{exprs: []string{"image=busybox*"}, match: true},
+		{exprs: []string{"image!=busybox*"}, match: false},
+		{exprs: []string{"exitCode>0"}, match: true},
+		{exprs: []string{"exitCode>137"}, match: false},
+		{exprs: []string{"exitCode>=137"}, match: true},
+		{exprs: []string{"exitCode<128"}, match: false},
+		{exprs: []string{"exitCode>=1", "exitCode<=255"}, match: true},
+		{exprs: []string{"exitCode>=1", "exitCode<=127"}, match: false},
+		{exprs: []string{"image>0"}, match: false},
+		{exprs: []string{"missing<1"}, match: false},
+	}
+	for _, tc := range cases {
+		args := NewArgs()
+		for _, expr := range tc.exprs {
+			args.Add("attr", expr)
+		}
+		assert.Check(t, args.MatchAttrs("attr", attrs) == tc.match, "%v", tc.exprs)
+	}
+}
+
+func TestValidateAttrs(t *testing.T) {
+	for _, expr := range []string{"exitCode", "exitCode>0", "signal!=9", "image=busy*", "a<=1.5"} {
+		assert.Check(t, NewArgs(Arg("attr", expr)).ValidateAttrs("attr"), expr)
+	}
+	for _, expr := range []string{"=9", "!=9", "exitCode>abc", "exitCode!9", "image=[", "exitCode<"} {
+		assert.Check(t, NewArgs(Arg("attr", expr)).ValidateAttrs("attr") != nil, expr)
+	}
+}
@@ -5,7 +5,9 @@ package filters // import "github.com/docker/docker/api/types/filters"
 
 import (
 	"encoding/json"
+	"path"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/docker/docker/api/types/versions"
@@ -232,12 +234,38 @@ func (args Args) FuzzyMatch(key, source string) bool {
 	return false
 }
 
+// GlobMatch returns true if the source matches one of the shell patterns
+// (see path.Match) at key, or if there are no values at key.
+func (args Args) GlobMatch(key, source string) bool {
+	if args.ExactMatch(key, source) {
+		return true
+	}
+
+	fieldValues := args.fields[key]
+	for pattern := range fieldValues {
+		if match, err := path.Match(pattern, source); err == nil && match {
+			return true
+		}
+	}
+	return false
+}
+
 // Contains returns true if the key exists in the mapping
 func (args Args) Contains(field string) bool {
 	_, ok := args.fields[field]
 	return ok
 }
 
+// Keys returns the sorted list of keys in the mapping
+func (args Args) Keys() []string {
+	keys := make([]string, 0, len(args.fields))
+	for k := range args.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 type invalidFilter string
 
 func (e invalidFilter) Error() string {
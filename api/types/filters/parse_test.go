@@ -384,3 +384,28 @@ func TestClone(t *testing.T) {
 	f2.Add("baz", "qux")
 	assert.Check(t, is.Len(f.Get("baz"), 0))
 }
+
+func TestGlobMatch(t *testing.T) {
+	f := NewArgs()
+	f.Add("name", "web-*")
+
+	cases := map[string]bool{
+		"web-1":  true,
+		"web-":   true,
+		"web":    false,
+		"db-web": false,
+	}
+	for source, match := range cases {
+		got := f.GlobMatch("name", source)
+		if got != match {
+			t.Fatalf("Expected %v, got %v: %s", match, got, source)
+		}
+	}
+	assert.Check(t, NewArgs().GlobMatch("name", "web-1"))
+}
+
+func TestKeys(t *testing.T) {
+	f := NewArgs(Arg("type", "container"), Arg("event", "die"), Arg("event!", "start"))
+	assert.Check(t, is.DeepEqual(f.Keys(), []string{"event", "event!", "type"}))
+	assert.Check(t, is.Len(NewArgs().Keys(), 0))
+}
@@ -11,6 +11,7 @@
 	It has these top-level messages:
 		LogEntry
 		PartialLogEntryMetadata
+		LogAttr
 */
 package logdriver
 
@@ -37,6 +38,7 @@ type LogEntry struct {
 	Line               []byte                   `protobuf:"bytes,3,opt,name=line,proto3" json:"line,omitempty"`
 	Partial            bool                     `protobuf:"varint,4,opt,name=partial,proto3" json:"partial,omitempty"`
 	PartialLogMetadata *PartialLogEntryMetadata `protobuf:"bytes,5,opt,name=partial_log_metadata,json=partialLogMetadata" json:"partial_log_metadata,omitempty"`
+	Attrs              []*LogAttr               `protobuf:"bytes,6,rep,name=attrs" json:"attrs,omitempty"`
 }
 
 func (m *LogEntry) Reset()                    { *m = LogEntry{} }
@@ -79,6 +81,13 @@ func (m *LogEntry) GetPartialLogMetadata() *PartialLogEntryMetadata {
 	return nil
 }
 
+func (m *LogEntry) GetAttrs() []*LogAttr {
+	if m != nil {
+		return m.Attrs
+	}
+	return nil
+}
+
 type PartialLogEntryMetadata struct {
 	Last    bool   `protobuf:"varint,1,opt,name=last,proto3" json:"last,omitempty"`
 	Id      string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
@@ -111,9 +120,34 @@ func (m *PartialLogEntryMetadata) GetOrdinal() int32 {
 	return 0
 }
 
+type LogAttr struct {
+	Key   string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *LogAttr) Reset()                    { *m = LogAttr{} }
+func (m *LogAttr) String() string            { return proto.CompactTextString(m) }
+func (*LogAttr) ProtoMessage()               {}
+func (*LogAttr) Descriptor() ([]byte, []int) { return fileDescriptorEntry, []int{2} }
+
+func (m *LogAttr) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *LogAttr) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*LogEntry)(nil), "LogEntry")
 	proto.RegisterType((*PartialLogEntryMetadata)(nil), "PartialLogEntryMetadata")
+	proto.RegisterType((*LogAttr)(nil), "LogAttr")
 }
 func (m *LogEntry) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -167,6 +201,18 @@ func (m *LogEntry) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n1
 	}
+	if len(m.Attrs) > 0 {
+		for _, msg := range m.Attrs {
+			dAtA[i] = 0x32
+			i++
+			i = encodeVarintEntry(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -209,6 +255,36 @@ func (m *PartialLogEntryMetadata) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *LogAttr) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LogAttr) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Key) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintEntry(dAtA, i, uint64(len(m.Key)))
+		i += copy(dAtA[i:], m.Key)
+	}
+	if len(m.Value) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintEntry(dAtA, i, uint64(len(m.Value)))
+		i += copy(dAtA[i:], m.Value)
+	}
+	return i, nil
+}
+
 func encodeFixed64Entry(dAtA []byte, offset int, v uint64) int {
 	dAtA[offset] = uint8(v)
 	dAtA[offset+1] = uint8(v >> 8)
@@ -257,6 +333,12 @@ func (m *LogEntry) Size() (n int) {
 		l = m.PartialLogMetadata.Size()
 		n += 1 + l + sovEntry(uint64(l))
 	}
+	if len(m.Attrs) > 0 {
+		for _, e := range m.Attrs {
+			l = e.Size()
+			n += 1 + l + sovEntry(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -276,6 +358,20 @@ func (m *PartialLogEntryMetadata) Size() (n int) {
 	return n
 }
 
+func (m *LogAttr) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovEntry(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovEntry(uint64(l))
+	}
+	return n
+}
+
 func sovEntry(x uint64) (n int) {
 	for {
 		n++
@@ -450,6 +546,37 @@ func (m *LogEntry) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attrs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEntry
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEntry
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attrs = append(m.Attrs, &LogAttr{})
+			if err := m.Attrs[len(m.Attrs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipEntry(dAtA[iNdEx:])
@@ -589,6 +716,114 @@ func (m *PartialLogEntryMetadata) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *LogAttr) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEntry
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LogAttr: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LogAttr: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEntry
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEntry
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEntry
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthEntry
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEntry(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthEntry
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipEntry(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -697,20 +932,23 @@ var (
 func init() { proto.RegisterFile("entry.proto", fileDescriptorEntry) }
 
 var fileDescriptorEntry = []byte{
-	// 237 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x90, 0xbd, 0x4a, 0x04, 0x31,
-	0x14, 0x85, 0xb9, 0xb3, 0x3f, 0xce, 0xdc, 0x5d, 0x2c, 0x82, 0x68, 0x40, 0x18, 0xc2, 0x56, 0xa9,
-	0xb6, 0xd0, 0x37, 0x10, 0x6c, 0x44, 0x45, 0xd2, 0x58, 0x0e, 0x57, 0x27, 0x2c, 0x81, 0xd9, 0xdc,
-	0x21, 0x13, 0x0b, 0x1f, 0xcd, 0x37, 0xb0, 0xf4, 0x11, 0x64, 0x9e, 0x44, 0x26, 0x4e, 0xec, 0xec,
-	0xce, 0x39, 0x5f, 0x8a, 0x2f, 0x17, 0x37, 0xd6, 0xc7, 0xf0, 0xbe, 0xef, 0x03, 0x47, 0xde, 0x7d,
-	0x00, 0x96, 0xf7, 0x7c, 0xb8, 0x9d, 0x26, 0x71, 0x8e, 0xeb, 0x81, 0xdf, 0xc2, 0xab, 0x95, 0xa0,
-	0x40, 0x57, 0x66, 0x6e, 0xe2, 0x12, 0xab, 0xe8, 0x8e, 0xb6, 0xf1, 0xe4, 0x59, 0x16, 0x0a, 0xf4,
-	0xc2, 0x94, 0xd3, 0xf0, 0x48, 0x9e, 0x85, 0xc0, 0x65, 0xe7, 0xbc, 0x95, 0x0b, 0x05, 0x7a, 0x6b,
-	0x52, 0x16, 0x12, 0x4f, 0x7a, 0x0a, 0xd1, 0x51, 0x27, 0x97, 0x0a, 0x74, 0x69, 0x72, 0x15, 0x77,
-	0x78, 0x36, 0xc7, 0xa6, 0xe3, 0x43, 0x73, 0xb4, 0x91, 0x5a, 0x8a, 0x24, 0x57, 0x0a, 0xf4, 0xe6,
-	0x4a, 0xee, 0x9f, 0x7e, 0x61, 0x56, 0x7a, 0x98, 0xb9, 0x11, 0xfd, 0x1f, 0xc8, 0xdb, 0xee, 0x19,
-	0x2f, 0xfe, 0x79, 0x9e, 0xa4, 0x68, 0x88, 0xe9, 0x1f, 0xa5, 0x49, 0x59, 0x9c, 0x62, 0xe1, 0xda,
-	0xa4, 0x5f, 0x99, 0xc2, 0xb5, 0x93, 0x24, 0x87, 0xd6, 0x79, 0xea, 0x92, 0xfb, 0xca, 0xe4, 0x7a,
-	0xb3, 0xfd, 0x1c, 0x6b, 0xf8, 0x1a, 0x6b, 0xf8, 0x1e, 0x6b, 0x78, 0x59, 0xa7, 0x4b, 0x5d, 0xff,
-	0x04, 0x00, 0x00, 0xff, 0xff, 0x8f, 0xed, 0x9f, 0xb6, 0x38, 0x01, 0x00, 0x00,
+	// 278 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x90, 0xcd, 0x4a, 0xc4, 0x30,
+	0x10, 0xc7, 0xc9, 0x76, 0xbb, 0xdb, 0x4e, 0x17, 0x91, 0xb0, 0x68, 0x40, 0x28, 0xa1, 0xa7, 0x9c,
+	0x0a, 0xae, 0x4f, 0xa0, 0xe0, 0x45, 0x56, 0x91, 0x5c, 0x3c, 0x96, 0x68, 0x43, 0x09, 0x76, 0x93,
+	0x92, 0x66, 0x85, 0x7d, 0x43, 0x8f, 0x5e, 0xbd, 0x49, 0x9f, 0x44, 0x9a, 0xa6, 0xde, 0xbc, 0xfd,
+	0x3f, 0x60, 0x66, 0x7e, 0x03, 0x99, 0xd4, 0xce, 0x9e, 0xca, 0xce, 0x1a, 0x67, 0x8a, 0x6f, 0x04,
+	0xc9, 0xde, 0x34, 0xf7, 0x63, 0x84, 0x2f, 0x60, 0xd5, 0x9b, 0xa3, 0x7d, 0x93, 0x04, 0x51, 0xc4,
+	0x52, 0x1e, 0x1c, 0xbe, 0x82, 0xd4, 0xa9, 0x83, 0xac, 0xb4, 0xd0, 0x86, 0x2c, 0x28, 0x62, 0x11,
+	0x4f, 0xc6, 0xe0, 0x49, 0x68, 0x83, 0x31, 0x2c, 0x5b, 0xa5, 0x25, 0x89, 0x28, 0x62, 0x1b, 0xee,
+	0x35, 0x26, 0xb0, 0xee, 0x84, 0x75, 0x4a, 0xb4, 0x64, 0x49, 0x11, 0x4b, 0xf8, 0x6c, 0xf1, 0x03,
+	0x6c, 0x83, 0xac, 0x5a, 0xd3, 0x54, 0x07, 0xe9, 0x44, 0x2d, 0x9c, 0x20, 0x31, 0x45, 0x2c, 0xdb,
+	0x91, 0xf2, 0x79, 0x2a, 0xe7, 0x93, 0x1e, 0x43, 0xcf, 0x71, 0xf7, 0x57, 0xcc, 0x19, 0xce, 0x21,
+	0x16, 0xce, 0xd9, 0x9e, 0xac, 0x68, 0xc4, 0xb2, 0x5d, 0x52, 0xee, 0x4d, 0x73, 0xeb, 0x9c, 0xe5,
+	0x53, 0x5c, 0xbc, 0xc0, 0xe5, 0x3f, 0xe3, 0xfc, 0xd1, 0xa2, 0x77, 0x9e, 0x33, 0xe1, 0x5e, 0xe3,
+	0x33, 0x58, 0xa8, 0xda, 0xe3, 0xa5, 0x7c, 0xa1, 0xea, 0x11, 0xc2, 0xd8, 0x5a, 0x69, 0xd1, 0x7a,
+	0xb6, 0x98, 0xcf, 0xb6, 0xb8, 0x86, 0x75, 0x58, 0x85, 0xcf, 0x21, 0x7a, 0x97, 0xa7, 0xf0, 0xaf,
+	0x51, 0xe2, 0x2d, 0xc4, 0x1f, 0xa2, 0x3d, 0xca, 0x30, 0x69, 0x32, 0x77, 0x9b, 0xcf, 0x21, 0x47,
+	0x5f, 0x43, 0x8e, 0x7e, 0x86, 0x1c, 0xbd, 0xae, 0xfc, 0xf3, 0x6f, 0x7e, 0x07, 0x00, 0x14, 0x9b,
+	0x26, 0x38, 0x8b, 0x01, 0x00, 0x00,
 }
@@ -69,6 +69,8 @@ type MemoryStats struct {
 	// number of times memory usage hits limits.
 	Failcnt uint64 `json:"failcnt,omitempty"`
 	Limit   uint64 `json:"limit,omitempty"`
+	// memory events of the cgroup of the container.
+	Events *MemoryEvents `json:"events,omitempty"`
 
 	// Windows Memory Stats
 	// See https://technet.microsoft.com/en-us/magazine/ff382715.aspx
@@ -81,6 +83,62 @@ type MemoryStats struct {
 	PrivateWorkingSet uint64 `json:"privateworkingset,omitempty"`
 }
 
+// MemoryEvents counts the memory events of the cgroup of a container, as
+// reported by memory.events with cgroup v2. Only OOMKill is reported with
+// cgroup v1.
+type MemoryEvents struct {
+	// number of times the memory usage was below the low boundary.
+	Low uint64 `json:"low"`
+	// number of times the memory usage was over the high boundary.
+	High uint64 `json:"high"`
+	// number of times the memory usage was about to go over the limit.
+	Max uint64 `json:"max"`
+	// number of times the memory usage reached the limit.
+	OOM uint64 `json:"oom"`
+	// number of processes killed by the OOM killer.
+	OOMKill uint64 `json:"oom_kill"`
+}
+
+// PressureData is the pressure stall information of a resource for a class of
+// tasks, see https://www.kernel.org/doc/html/latest/accounting/psi.html
+type PressureData struct {
+	// percentage of time the tasks were stalled over the last 10, 60 and
+	// 300 seconds.
+	Avg10  float64 `json:"avg10"`
+	Avg60  float64 `json:"avg60"`
+	Avg300 float64 `json:"avg300"`
+	// total stall time in microseconds.
+	Total uint64 `json:"total"`
+}
+
+// Pressure is the pressure stall information of a resource, when some of the
+// tasks of the container were stalled, and when all of them were.
+type Pressure struct {
+	Some PressureData `json:"some"`
+	Full PressureData `json:"full"`
+}
+
+// PressureStats is the pressure stall information of the cgroup of a
+// container. Only reported with cgroup v2.
+type PressureStats struct {
+	CPU    *Pressure `json:"cpu,omitempty"`
+	Memory *Pressure `json:"memory,omitempty"`
+	IO     *Pressure `json:"io,omitempty"`
+}
+
+// CPUThrottling is the CPU throttling of a container between the previous
+// read and this one. Linux only.
+type CPUThrottling struct {
+	// number of periods with throttling active.
+	Periods uint64 `json:"periods"`
+	// number of periods when the container hit its throttling limit.
+	ThrottledPeriods uint64 `json:"throttled_periods"`
+	// aggregate time the container was throttled for in nanoseconds.
+	ThrottledTime uint64 `json:"throttled_time"`
+	// percentage of the periods when the container was throttled.
+	ThrottledPercent float64 `json:"throttled_percent"`
+}
+
 // BlkioStatEntry is one small entity to store a piece of Blkio stats
 // Not used on Windows.
 type BlkioStatEntry struct {
@@ -156,8 +214,10 @@ type Stats struct {
 	PreRead time.Time `json:"preread"`
 
 	// Linux specific stats, not populated on Windows.
-	PidsStats  PidsStats  `json:"pids_stats,omitempty"`
-	BlkioStats BlkioStats `json:"blkio_stats,omitempty"`
+	PidsStats     PidsStats      `json:"pids_stats,omitempty"`
+	BlkioStats    BlkioStats     `json:"blkio_stats,omitempty"`
+	PressureStats *PressureStats `json:"pressure_stats,omitempty"`
+	CPUThrottling *CPUThrottling `json:"cpu_throttling,omitempty"` // since the previous read
 
 	// Windows specific stats, not populated on Linux.
 	NumProcs     uint32       `json:"num_procs"`
@@ -179,3 +239,39 @@ type StatsJSON struct {
 	// Networks request version >=1.21
 	Networks map[string]NetworkStats `json:"networks,omitempty"`
 }
+
+// SystemStats is the stats of the running containers of a host, returned by
+// the /system/stats endpoint.
+type SystemStats struct {
+	Read       time.Time         `json:"read"`
+	Containers []StatsJSON       `json:"containers"`
+	Totals     SystemStatsTotals `json:"totals"`
+}
+
+// SystemStatsTotals aggregates the stats of the containers of SystemStats.
+type SystemStatsTotals struct {
+	// number of containers.
+	Containers int `json:"containers"`
+	// total CPU time consumed by the containers, in nanoseconds.
+	CPUUsage uint64 `json:"cpu_usage"`
+	// CPU usage of the containers since the previous read, in percent of
+	// one CPU. Linux only.
+	CPUPercent float64 `json:"cpu_percent"`
+	// System Usage. Linux only.
+	SystemUsage uint64 `json:"system_cpu_usage,omitempty"`
+	// Online CPUs. Linux only.
+	OnlineCPUs uint32 `json:"online_cpus,omitempty"`
+	// memory usage of the containers.
+	MemoryUsage uint64 `json:"memory_usage"`
+	// memory of the host. Linux only.
+	MemoryLimit uint64 `json:"memory_limit,omitempty"`
+	// bytes received and sent by the containers.
+	NetworkRxBytes uint64 `json:"network_rx_bytes"`
+	NetworkTxBytes uint64 `json:"network_tx_bytes"`
+	// bytes read from and written to block devices by the containers.
+	// Linux only.
+	BlkioReadBytes  uint64 `json:"blkio_read_bytes"`
+	BlkioWriteBytes uint64 `json:"blkio_write_bytes"`
+	// number of processes of the containers. Linux only.
+	Pids uint64 `json:"pids"`
+}
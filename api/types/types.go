@@ -51,6 +51,9 @@ type ImageInspect struct {
 // ImageMetadata contains engine-local data about the image
 type ImageMetadata struct {
 	LastTagTime time.Time `json:",omitempty"`
+	// Protected images are skipped by prune, and can only be removed with
+	// force.
+	Protected bool `json:",omitempty"`
 }
 
 // Container contains response of Engine API:
@@ -292,9 +295,23 @@ const (
 	Unhealthy     = "unhealthy" // Unhealthy indicates that the container has a problem
 )
 
+// Readiness states
+const (
+	Ready    = "ready"     // Ready indicates that the container is ready to serve requests
+	NotReady = "not-ready" // NotReady indicates that the container is running but not ready to serve requests
+)
+
 // Health stores information about the container's healthcheck results
 type Health struct {
-	Status        string               // Status is one of Starting, Healthy or Unhealthy
+	Status        string               // Status is one of Starting, Healthy, Unhealthy, or NoHealthcheck if only a readiness check is configured
+	FailingStreak int                  // FailingStreak is the number of consecutive failures
+	Log           []*HealthcheckResult // Log contains the last few results (oldest first)
+	Readiness     *Readiness           `json:",omitempty"` // Readiness contains the readiness check results, if configured
+}
+
+// Readiness stores information about the container's readiness check results
+type Readiness struct {
+	Status        string               // Status is one of Starting, Ready or NotReady
 	FailingStreak int                  // FailingStreak is the number of consecutive failures
 	Log           []*HealthcheckResult // Log contains the last few results (oldest first)
 }
@@ -302,18 +319,19 @@ type Health struct {
 // ContainerState stores container's running state
 // it's part of ContainerJSONBase and will return by "inspect" command
 type ContainerState struct {
-	Status     string // String representation of the container state. Can be one of "created", "running", "paused", "restarting", "removing", "exited", or "dead"
-	Running    bool
-	Paused     bool
-	Restarting bool
-	OOMKilled  bool
-	Dead       bool
-	Pid        int
-	ExitCode   int
-	Error      string
-	StartedAt  string
-	FinishedAt string
-	Health     *Health `json:",omitempty"`
+	Status       string // String representation of the container state. Can be one of "created", "running", "paused", "restarting", "removing", "exited", or "dead"
+	Running      bool
+	Paused       bool
+	Restarting   bool
+	CrashLooping bool
+	OOMKilled    bool
+	Dead         bool
+	Pid          int
+	ExitCode     int
+	Error        string
+	StartedAt    string
+	FinishedAt   string
+	Health       *Health `json:",omitempty"`
 }
 
 // ContainerNode stores information about the node that a container
@@ -30,6 +30,10 @@ type Volume struct {
 	// Required: true
 	Options map[string]string `json:"Options"`
 
+	// Whether the volume is protected from prune, and from removal without
+	// force.
+	Protected bool `json:"Protected,omitempty"`
+
 	// The level at which the volume exists. Either `global` for cluster-wide, or `local` for machine level.
 	// Required: true
 	Scope string `json:"Scope"`
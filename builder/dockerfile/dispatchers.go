@@ -463,6 +463,16 @@ func dispatchCmd(d dispatchRequest, c *instructions.CmdCommand) error {
 //
 func dispatchHealthcheck(d dispatchRequest, c *instructions.HealthCheckCommand) error {
 	runConfig := d.state.runConfig
+	if c.Readiness {
+		if runConfig.Readinesscheck != nil {
+			oldCmd := runConfig.Readinesscheck.Test
+			if len(oldCmd) > 0 && oldCmd[0] != "NONE" {
+				fmt.Fprintf(d.builder.Stdout, "Note: overriding previous HEALTHCHECK --readiness: %v\n", oldCmd)
+			}
+		}
+		runConfig.Readinesscheck = c.Health
+		return d.builder.commit(d.state, fmt.Sprintf("HEALTHCHECK --readiness %q", runConfig.Readinesscheck))
+	}
 	if runConfig.Healthcheck != nil {
 		oldCmd := runConfig.Healthcheck.Test
 		if len(oldCmd) > 0 && oldCmd[0] != "NONE" {
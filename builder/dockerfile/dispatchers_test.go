@@ -299,6 +299,24 @@ func TestHealthcheckCmd(t *testing.T) {
 	assert.Check(t, is.DeepEqual(expectedTest, sb.state.runConfig.Healthcheck.Test))
 }
 
+func TestHealthcheckReadiness(t *testing.T) {
+	b := newBuilderWithMockBackend()
+	sb := newDispatchRequest(b, '`', nil, NewBuildArgs(make(map[string]*string)), newStagesBuildResults())
+	expectedTest := []string{"CMD-SHELL", "curl -f http://localhost/ready || exit 1"}
+	cmd := &instructions.HealthCheckCommand{
+		Health: &container.HealthConfig{
+			Test: expectedTest,
+		},
+		Readiness: true,
+	}
+	err := dispatch(sb, cmd)
+	assert.NilError(t, err)
+
+	assert.Check(t, is.Nil(sb.state.runConfig.Healthcheck))
+	assert.Assert(t, sb.state.runConfig.Readinesscheck != nil)
+	assert.Check(t, is.DeepEqual(expectedTest, sb.state.runConfig.Readinesscheck.Test))
+}
+
 func TestEntrypoint(t *testing.T) {
 	b := newBuilderWithMockBackend()
 	sb := newDispatchRequest(b, '`', nil, NewBuildArgs(make(map[string]*string)), newStagesBuildResults())
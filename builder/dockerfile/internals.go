@@ -355,15 +355,18 @@ func withEntrypointOverride(cmd []string, entrypoint []string) runConfigModifier
 	}
 }
 
-// withoutHealthcheck disables healthcheck.
+// withoutHealthcheck disables healthcheck and readiness check.
 //
 // The dockerfile RUN instruction expect to run without healthcheck
-// so the runConfig Healthcheck needs to be disabled.
+// so the runConfig Healthcheck and Readinesscheck need to be disabled.
 func withoutHealthcheck() runConfigModifier {
 	return func(runConfig *container.Config) {
 		runConfig.Healthcheck = &container.HealthConfig{
 			Test: []string{"NONE"},
 		}
+		runConfig.Readinesscheck = &container.HealthConfig{
+			Test: []string{"NONE"},
+		}
 	}
 }
 
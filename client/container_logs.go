@@ -72,6 +72,16 @@ func (cli *Client) ContainerLogs(ctx context.Context, container string, options
 	}
 	query.Set("tail", options.Tail)
 
+	for _, include := range options.Include {
+		query.Add("include", include)
+	}
+	for _, exclude := range options.Exclude {
+		query.Add("exclude", exclude)
+	}
+	for k, v := range options.Attrs {
+		query.Add("attrs", k+"="+v)
+	}
+
 	resp, err := cli.get(ctx, "/containers/"+container+"/logs", query, nil)
 	if err != nil {
 		return nil, wrapResponseError(err, resp, "container", container)
@@ -3,8 +3,11 @@ package client // import "github.com/docker/docker/client"
 import (
 	"context"
 	"net/url"
+	"time"
 
 	"github.com/docker/docker/api/types"
+	timetypes "github.com/docker/docker/api/types/time"
+	"github.com/pkg/errors"
 )
 
 // ContainerStats returns near realtime stats for a given container.
@@ -24,3 +27,42 @@ func (cli *Client) ContainerStats(ctx context.Context, containerID string, strea
 	osType := getDockerOS(resp.header.Get("Server"))
 	return types.ContainerStats{Body: resp.body, OSType: osType}, err
 }
+
+// ContainerStatsHistory returns the stats samples kept by the daemon for a
+// given container, between options.Since and options.Until. The body is a
+// stream of JSON objects, like the body of a stream of live stats.
+// It's up to the caller to close the io.ReadCloser returned.
+func (cli *Client) ContainerStatsHistory(ctx context.Context, containerID string, options types.ContainerStatsHistoryOptions) (types.ContainerStats, error) {
+	if err := cli.NewVersionError("1.41", "stats history"); err != nil {
+		return types.ContainerStats{}, err
+	}
+
+	query := url.Values{}
+	// the history is selected by setting any of since, until or resolution
+	query.Set("until", "0")
+	if options.Since != "" {
+		ts, err := timetypes.GetTimestamp(options.Since, time.Now())
+		if err != nil {
+			return types.ContainerStats{}, errors.Wrap(err, `invalid value for "since"`)
+		}
+		query.Set("since", ts)
+	}
+	if options.Until != "" {
+		ts, err := timetypes.GetTimestamp(options.Until, time.Now())
+		if err != nil {
+			return types.ContainerStats{}, errors.Wrap(err, `invalid value for "until"`)
+		}
+		query.Set("until", ts)
+	}
+	if options.Resolution != "" {
+		query.Set("resolution", options.Resolution)
+	}
+
+	resp, err := cli.get(ctx, "/containers/"+containerID+"/stats", query, nil)
+	if err != nil {
+		return types.ContainerStats{}, err
+	}
+
+	osType := getDockerOS(resp.header.Get("Server"))
+	return types.ContainerStats{Body: resp.body, OSType: osType}, err
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/errdefs"
 )
 
@@ -72,3 +73,40 @@ func TestContainerStats(t *testing.T) {
 		}
 	}
 }
+
+func TestContainerStatsHistory(t *testing.T) {
+	expectedURL := "/containers/container_id/stats"
+	client := &Client{
+		version: "1.41",
+		client: newMockClient(func(r *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(r.URL.Path, "/v1.41"+expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, r.URL)
+			}
+			query := r.URL.Query()
+			for k, v := range map[string]string{"since": "1577836800", "until": "0", "resolution": "10s"} {
+				if query.Get(k) != v {
+					return nil, fmt.Errorf("%s not set in URL query properly. Expected '%s', got %s", k, v, query.Get(k))
+				}
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte("response"))),
+			}, nil
+		}),
+	}
+	resp, err := client.ContainerStatsHistory(context.Background(), "container_id", types.ContainerStatsHistoryOptions{
+		Since:      "1577836800",
+		Resolution: "10s",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "response" {
+		t.Fatalf("expected response to contain 'response', got %s", string(content))
+	}
+}
@@ -0,0 +1,23 @@
+package client // import "github.com/docker/docker/client"
+
+import "context"
+
+// ImageProtect protects an image from prune, and from removal without force.
+func (cli *Client) ImageProtect(ctx context.Context, imageID string) error {
+	if err := cli.NewVersionError("1.41", "image protect"); err != nil {
+		return err
+	}
+	resp, err := cli.post(ctx, "/images/"+imageID+"/protect", nil, nil, nil)
+	ensureReaderClosed(resp)
+	return err
+}
+
+// ImageUnprotect removes the protection of an image.
+func (cli *Client) ImageUnprotect(ctx context.Context, imageID string) error {
+	if err := cli.NewVersionError("1.41", "image unprotect"); err != nil {
+		return err
+	}
+	resp, err := cli.post(ctx, "/images/"+imageID+"/unprotect", nil, nil, nil)
+	ensureReaderClosed(resp)
+	return err
+}
@@ -0,0 +1,57 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+)
+
+func TestImageProtectError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	err := client.ImageProtect(context.Background(), "nothing")
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %[1]T: %[1]v", err)
+	}
+}
+
+func TestImageProtect(t *testing.T) {
+	for _, tc := range []struct {
+		protect     func(*Client) error
+		expectedURL string
+	}{
+		{
+			protect:     func(c *Client) error { return c.ImageProtect(context.Background(), "image_id") },
+			expectedURL: "/images/image_id/protect",
+		},
+		{
+			protect:     func(c *Client) error { return c.ImageUnprotect(context.Background(), "image_id") },
+			expectedURL: "/images/image_id/unprotect",
+		},
+	} {
+		expectedURL := tc.expectedURL
+		client := &Client{
+			client: newMockClient(func(req *http.Request) (*http.Response, error) {
+				if req.URL.Path != expectedURL {
+					return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+				}
+				if req.Method != http.MethodPost {
+					return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+				}
+				return &http.Response{
+					StatusCode: http.StatusNoContent,
+					Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+				}, nil
+			}),
+		}
+		if err := tc.protect(client); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
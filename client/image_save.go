@@ -4,14 +4,29 @@ import (
 	"context"
 	"io"
 	"net/url"
+
+	"github.com/docker/docker/api/types"
 )
 
 // ImageSave retrieves one or more images from the docker host as an io.ReadCloser.
 // It's up to the caller to store the images and close the stream.
 func (cli *Client) ImageSave(ctx context.Context, imageIDs []string) (io.ReadCloser, error) {
+	return cli.ImageSaveWithOptions(ctx, imageIDs, types.ImageSaveOptions{})
+}
+
+// ImageSaveWithOptions retrieves one or more images from the docker host as an
+// io.ReadCloser, in the format of options.Format.
+// It's up to the caller to store the images and close the stream.
+func (cli *Client) ImageSaveWithOptions(ctx context.Context, imageIDs []string, options types.ImageSaveOptions) (io.ReadCloser, error) {
 	query := url.Values{
 		"names": imageIDs,
 	}
+	if options.Format != "" {
+		if err := cli.NewVersionError("1.41", "image save format"); err != nil {
+			return nil, err
+		}
+		query.Set("format", options.Format)
+	}
 
 	resp, err := cli.get(ctx, "/images/get", query, nil)
 	if err != nil {
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/errdefs"
 )
 
@@ -59,3 +60,27 @@ func TestImageSave(t *testing.T) {
 		t.Fatalf("expected response to contain 'response', got %s", string(response))
 	}
 }
+
+func TestImageSaveWithOptions(t *testing.T) {
+	client := &Client{
+		client: newMockClient(func(r *http.Request) (*http.Response, error) {
+			if format := r.URL.Query().Get("format"); format != "oci" {
+				return nil, fmt.Errorf("format not set in URL query properly. Expected oci, got %q", format)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte("response"))),
+			}, nil
+		}),
+	}
+	saveResponse, err := client.ImageSaveWithOptions(context.Background(), []string{"image_id1"}, types.ImageSaveOptions{Format: "oci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	saveResponse.Close()
+
+	client.version = "1.40"
+	if _, err := client.ImageSaveWithOptions(context.Background(), []string{"image_id1"}, types.ImageSaveOptions{Format: "oci"}); err == nil {
+		t.Fatal("expected an error with API version 1.40")
+	}
+}
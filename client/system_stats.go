@@ -0,0 +1,39 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"io"
+	"net/url"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// SystemStats returns the stats of the running containers of the host, with
+// their totals, as a stream of JSON types.SystemStats objects, or a single
+// one if options.Stream is not set.
+// It's up to the caller to close the io.ReadCloser returned.
+func (cli *Client) SystemStats(ctx context.Context, options types.SystemStatsOptions) (io.ReadCloser, error) {
+	if err := cli.NewVersionError("1.41", "system stats"); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("stream", "0")
+	if options.Stream {
+		query.Set("stream", "1")
+	}
+	if options.Filters.Len() > 0 {
+		filterJSON, err := filters.ToJSON(options.Filters)
+		if err != nil {
+			return nil, err
+		}
+		query.Set("filters", filterJSON)
+	}
+
+	resp, err := cli.get(ctx, "/system/stats", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.body, nil
+}
@@ -0,0 +1,62 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+)
+
+func TestSystemStatsError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.SystemStats(context.Background(), types.SystemStatsOptions{})
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %[1]T: %[1]v", err)
+	}
+}
+
+func TestSystemStats(t *testing.T) {
+	expectedURL := "/system/stats"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			query := req.URL.Query()
+			if stream := query.Get("stream"); stream != "1" {
+				return nil, fmt.Errorf("stream not set in URL query properly. Expected '1', got %s", stream)
+			}
+			if f := query.Get("filters"); f != `{"label":{"com.example.team=frontend":true}}` {
+				return nil, fmt.Errorf("filters not set in URL query properly, got %s", f)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte("response"))),
+			}, nil
+		}),
+	}
+	body, err := client.SystemStats(context.Background(), types.SystemStatsOptions{
+		Stream:  true,
+		Filters: filters.NewArgs(filters.Arg("label", "com.example.team=frontend")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "response" {
+		t.Fatalf("expected response to contain 'response', got %s", string(content))
+	}
+}
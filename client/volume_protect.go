@@ -0,0 +1,23 @@
+package client // import "github.com/docker/docker/client"
+
+import "context"
+
+// VolumeProtect protects a volume from prune, and from removal without force.
+func (cli *Client) VolumeProtect(ctx context.Context, volumeID string) error {
+	if err := cli.NewVersionError("1.41", "volume protect"); err != nil {
+		return err
+	}
+	resp, err := cli.post(ctx, "/volumes/"+volumeID+"/protect", nil, nil, nil)
+	ensureReaderClosed(resp)
+	return err
+}
+
+// VolumeUnprotect removes the protection of a volume.
+func (cli *Client) VolumeUnprotect(ctx context.Context, volumeID string) error {
+	if err := cli.NewVersionError("1.41", "volume unprotect"); err != nil {
+		return err
+	}
+	resp, err := cli.post(ctx, "/volumes/"+volumeID+"/unprotect", nil, nil, nil)
+	ensureReaderClosed(resp)
+	return err
+}
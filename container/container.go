@@ -23,6 +23,7 @@ import (
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/daemon/logger/jsonfilelog"
 	"github.com/docker/docker/daemon/logger/local"
+	"github.com/docker/docker/daemon/logger/loggerutils/cache"
 	"github.com/docker/docker/daemon/network"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/image"
@@ -405,6 +406,27 @@ func (container *Container) StartLogger() (logger.Logger, error) {
 		return nil, err
 	}
 
+	if _, ok := l.(logger.LogReader); !ok && cache.Enabled(cfg.Config) {
+		// Keep a local copy of the logs so that they can be read, even though
+		// the logging driver does not support it.
+		logDir, err := container.GetRootResourcePath("container-cached-logs")
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+		if err := os.MkdirAll(logDir, 0700); err != nil {
+			l.Close()
+			return nil, errdefs.System(errors.Wrap(err, "error creating local log cache dir"))
+		}
+		info.LogPath = filepath.Join(logDir, "container-cached.log")
+		cached, err := cache.WithLocalCache(l, info)
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+		l = cached
+	}
+
 	if containertypes.LogMode(cfg.Config["mode"]) == containertypes.LogModeNonBlock {
 		bufferSize := int64(-1)
 		if s, exists := cfg.Config["max-buffer-size"]; exists {
@@ -616,7 +638,26 @@ func (container *Container) startLogging() error {
 		return fmt.Errorf("failed to initialize logging driver: %v", err)
 	}
 
+	limiter, err := logger.NewRateLimiter(container.HostConfig.LogConfig.Config)
+	if err != nil {
+		l.Close()
+		return fmt.Errorf("failed to initialize logging rate limit: %v", err)
+	}
+	multiline, err := logger.NewMultilineConfig(container.HostConfig.LogConfig.Config)
+	if err != nil {
+		l.Close()
+		return fmt.Errorf("failed to initialize multiline logging: %v", err)
+	}
+	parser, err := logger.NewLineParser(container.HostConfig.LogConfig.Config)
+	if err != nil {
+		l.Close()
+		return fmt.Errorf("failed to initialize log parsing: %v", err)
+	}
+
 	copier := logger.NewCopier(map[string]io.Reader{"stdout": container.StdoutPipe(), "stderr": container.StderrPipe()}, l)
+	copier.SetRateLimiter(limiter)
+	copier.SetMultiline(multiline)
+	copier.SetParser(parser)
 	container.LogCopier = copier
 	copier.Run()
 	container.LogDriver = l
@@ -1,7 +1,9 @@
 package container // import "github.com/docker/docker/container"
 
 import (
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/sirupsen/logrus"
@@ -12,18 +14,32 @@ type Health struct {
 	types.Health
 	stop chan struct{} // Write struct{} to stop the monitor
 	mu   sync.Mutex
+
+	unhealthySince time.Time // when the container last became unhealthy
 }
 
 // String returns a human-readable description of the health-check state
 func (s *Health) String() string {
-	status := s.Status()
+	var states []string
 
-	switch status {
+	switch status := s.Status(); status {
+	case types.NoHealthcheck:
+		// only a readiness check is configured
 	case types.Starting:
-		return "health: starting"
+		states = append(states, "health: starting")
 	default: // Healthy and Unhealthy are clear on their own
-		return s.Health.Status
+		states = append(states, status)
+	}
+
+	switch status := s.ReadinessStatus(); status {
+	case "":
+		// no readiness check is configured
+	case types.Starting:
+		states = append(states, "readiness: starting")
+	default: // Ready and NotReady are clear on their own
+		states = append(states, status)
 	}
+	return strings.Join(states, ", ")
 }
 
 // Status returns the current health status.
@@ -49,9 +65,50 @@ func (s *Health) SetStatus(new string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if new == types.Unhealthy && s.Health.Status != types.Unhealthy {
+		s.unhealthySince = time.Now()
+	}
 	s.Health.Status = new
 }
 
+// ReadinessStatus returns the current readiness status, or an empty string
+// if no readiness check is configured.
+//
+// Note that this takes a lock and the value may change after being read.
+func (s *Health) ReadinessStatus() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Health.Readiness == nil {
+		return ""
+	}
+	return s.Health.Readiness.Status
+}
+
+// SetReadinessStatus writes the current readiness status to the underlying
+// health structure, obeying the locking semantics.
+func (s *Health) SetReadinessStatus(new string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Health.Readiness == nil {
+		s.Health.Readiness = &types.Readiness{}
+	}
+	s.Health.Readiness.Status = new
+}
+
+// UnhealthySince returns the time at which the container last became
+// unhealthy, or the zero time if it is not currently unhealthy.
+func (s *Health) UnhealthySince() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Health.Status != types.Unhealthy {
+		return time.Time{}
+	}
+	return s.unhealthySince
+}
+
 // OpenMonitorChannel creates and returns a new monitor channel. If there
 // already is one, it returns nil.
 func (s *Health) OpenMonitorChannel() chan struct{} {
@@ -76,7 +133,12 @@ func (s *Health) CloseMonitorChannel() {
 		close(s.stop)
 		s.stop = nil
 		// unhealthy when the monitor has stopped for compatibility reasons
-		s.Health.Status = types.Unhealthy
+		if s.Health.Status != types.NoHealthcheck {
+			s.Health.Status = types.Unhealthy
+		}
+		if s.Health.Readiness != nil {
+			s.Health.Readiness.Status = types.NotReady
+		}
 		logrus.Debug("CloseMonitorChannel done")
 	}
 }
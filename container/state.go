@@ -23,6 +23,7 @@ type State struct {
 	Running           bool
 	Paused            bool
 	Restarting        bool
+	CrashLooping      bool // Set when the restart manager detected a crash loop
 	OOMKilled         bool
 	RemovalInProgress bool // Not need for this to be persistent on disk.
 	Dead              bool
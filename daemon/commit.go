@@ -74,27 +74,8 @@ func merge(userConf, imageConf *containertypes.Config) error {
 			userConf.Entrypoint = imageConf.Entrypoint
 		}
 	}
-	if imageConf.Healthcheck != nil {
-		if userConf.Healthcheck == nil {
-			userConf.Healthcheck = imageConf.Healthcheck
-		} else {
-			if len(userConf.Healthcheck.Test) == 0 {
-				userConf.Healthcheck.Test = imageConf.Healthcheck.Test
-			}
-			if userConf.Healthcheck.Interval == 0 {
-				userConf.Healthcheck.Interval = imageConf.Healthcheck.Interval
-			}
-			if userConf.Healthcheck.Timeout == 0 {
-				userConf.Healthcheck.Timeout = imageConf.Healthcheck.Timeout
-			}
-			if userConf.Healthcheck.StartPeriod == 0 {
-				userConf.Healthcheck.StartPeriod = imageConf.Healthcheck.StartPeriod
-			}
-			if userConf.Healthcheck.Retries == 0 {
-				userConf.Healthcheck.Retries = imageConf.Healthcheck.Retries
-			}
-		}
-	}
+	userConf.Healthcheck = mergeHealthConfig(userConf.Healthcheck, imageConf.Healthcheck)
+	userConf.Readinesscheck = mergeHealthConfig(userConf.Readinesscheck, imageConf.Readinesscheck)
 
 	if userConf.WorkingDir == "" {
 		userConf.WorkingDir = imageConf.WorkingDir
@@ -113,6 +94,33 @@ func merge(userConf, imageConf *containertypes.Config) error {
 	return nil
 }
 
+// mergeHealthConfig fills the unset fields of the user's health (or readiness)
+// check configuration from the image's.
+func mergeHealthConfig(userConf, imageConf *containertypes.HealthConfig) *containertypes.HealthConfig {
+	if imageConf == nil {
+		return userConf
+	}
+	if userConf == nil {
+		return imageConf
+	}
+	if len(userConf.Test) == 0 {
+		userConf.Test = imageConf.Test
+	}
+	if userConf.Interval == 0 {
+		userConf.Interval = imageConf.Interval
+	}
+	if userConf.Timeout == 0 {
+		userConf.Timeout = imageConf.Timeout
+	}
+	if userConf.StartPeriod == 0 {
+		userConf.StartPeriod = imageConf.StartPeriod
+	}
+	if userConf.Retries == 0 {
+		userConf.Retries = imageConf.Retries
+	}
+	return userConf
+}
+
 // CreateImageFromContainer creates a new image from a container. The container
 // config will be updated by applying the change set to the custom config, then
 // applying that config over the existing container config.
@@ -55,6 +55,7 @@ var flatOptions = map[string]bool{
 	"default-ulimits":    true,
 	"features":           true,
 	"builder":            true,
+	"events":             true,
 }
 
 // skipValidateOptions contains configuration keys
@@ -63,6 +64,7 @@ var flatOptions = map[string]bool{
 var skipValidateOptions = map[string]bool{
 	"features": true,
 	"builder":  true,
+	"events":   true,
 	// Corresponding flag has been removed because it was already unusable
 	"deprecated-key-path": true,
 }
@@ -204,6 +206,10 @@ type CommonConfig struct {
 
 	MetricsAddress string `json:"metrics-addr"`
 
+	// MetricsContainerLabels is the allowlist of the container labels added
+	// to the labels of the per-container metrics.
+	MetricsContainerLabels []string `json:"metrics-container-labels,omitempty"`
+
 	DNSConfig
 	LogConfig
 	BridgeConfig // bridgeConfig holds bridge network specific configuration.
@@ -236,6 +242,10 @@ type CommonConfig struct {
 
 	Builder BuilderConfig `json:"builder,omitempty"`
 
+	Events EventsConfig `json:"events,omitempty"`
+
+	ImageGC ImageGCConfig `json:"image-gc,omitempty"`
+
 	ContainerdNamespace       string `json:"containerd-namespace,omitempty"`
 	ContainerdPluginNamespace string `json:"containerd-plugin-namespace,omitempty"`
 }
@@ -583,6 +593,12 @@ func Validate(config *Config) error {
 		}
 	}
 
+	if config.ImageGC.Enabled {
+		if err := config.ImageGC.validate(); err != nil {
+			return err
+		}
+	}
+
 	// validate platform-specific settings
 	return config.ValidatePlatformConfig()
 }
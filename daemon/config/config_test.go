@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/daemon/discovery"
 	"github.com/docker/docker/opts"
 	"github.com/spf13/pflag"
@@ -313,6 +314,34 @@ func TestValidateConfigurationErrors(t *testing.T) {
 				},
 			},
 		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ImageGC: ImageGCConfig{Enabled: true, HighWatermark: 60, LowWatermark: 80},
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ImageGC: ImageGCConfig{Enabled: true, HighWatermark: 101},
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ImageGC: ImageGCConfig{Enabled: true, Interval: "often"},
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ImageGC: ImageGCConfig{Enabled: true, ExcludeLabels: []string{"=value"}},
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		err := Validate(tc.config)
@@ -388,6 +417,18 @@ func TestValidateConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ImageGC: ImageGCConfig{
+						Enabled:       true,
+						HighWatermark: 90,
+						Interval:      "1m",
+						ExcludeLabels: []string{"com.example.keep", "com.example.pin=true"},
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		err := Validate(tc.config)
@@ -528,3 +569,28 @@ func TestReloadWithDuplicateLabels(t *testing.T) {
 	err := Reload(configFile, flags, func(c *Config) {})
 	assert.Check(t, err)
 }
+
+func TestEventSinksConfiguration(t *testing.T) {
+	tempFile := fs.NewFile(t, "config", fs.WithContent(`{
+  "events": {
+    "sinks": [
+      {"name": "alerts", "type": "webhook", "address": "http://localhost:8080", "filter": {"type": ["container"], "event": ["die", "oom"]}},
+      {"name": "local", "type": "unix", "address": "/run/events.sock", "filter": {"type": {"image": true}}}
+    ]
+  }
+}`))
+	defer tempFile.Remove()
+
+	cc, err := MergeDaemonConfigurations(&Config{}, nil, tempFile.Path())
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(cc.Events.Sinks, 2))
+
+	alerts := filters.Args(cc.Events.Sinks[0].Filter)
+	assert.Check(t, is.Equal(cc.Events.Sinks[0].Type, "webhook"))
+	assert.Check(t, alerts.ExactMatch("type", "container"))
+	assert.Check(t, alerts.ExactMatch("event", "oom"))
+	assert.Check(t, !alerts.ExactMatch("event", "start"))
+
+	local := filters.Args(cc.Events.Sinks[1].Filter)
+	assert.Check(t, local.ExactMatch("type", "image"))
+}
@@ -0,0 +1,52 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"encoding/json"
+
+	"github.com/docker/docker/api/types/filters"
+)
+
+// EventsJournalConfig contains the configuration of the on-disk event journal
+type EventsJournalConfig struct {
+	Enabled bool   `json:",omitempty"`
+	MaxSize string `json:",omitempty"` // MaxSize is the maximum size of the journal, e.g. "64MB"
+	MaxAge  string `json:",omitempty"` // MaxAge is the maximum age of the journaled events, e.g. "24h"
+}
+
+// EventSinkFilter holds the filters of an event sink.
+// It accepts both the current and the legacy JSON format of filters.Args.
+type EventSinkFilter filters.Args
+
+// MarshalJSON returns a JSON byte representation of the EventSinkFilter
+func (x EventSinkFilter) MarshalJSON() ([]byte, error) {
+	f := filters.Args(x)
+	return json.Marshal(f)
+}
+
+// UnmarshalJSON fills the EventSinkFilter values structure from JSON input
+func (x *EventSinkFilter) UnmarshalJSON(data []byte) error {
+	f, err := filters.FromJSON(string(data))
+	if err != nil {
+		return err
+	}
+	*x = EventSinkFilter(f)
+	return nil
+}
+
+// EventSinkConfig contains the configuration of an event sink
+type EventSinkConfig struct {
+	Name         string          `json:",omitempty"`
+	Type         string          `json:",omitempty"` // Type is one of "webhook", "unix", or "unixgram"
+	Address      string          `json:",omitempty"` // Address is the URL of the webhook, or the path of the socket
+	Filter       EventSinkFilter `json:",omitempty"`
+	BatchSize    int             `json:",omitempty"`
+	BatchTimeout string          `json:",omitempty"`
+	MaxRetries   int             `json:",omitempty"`
+	SpoolSize    string          `json:",omitempty"` // SpoolSize is the maximum size of the on-disk spool, e.g. "16MB"
+}
+
+// EventsConfig contains config for the daemon events
+type EventsConfig struct {
+	Journal EventsJournalConfig `json:",omitempty"`
+	Sinks   []EventSinkConfig   `json:",omitempty"`
+}
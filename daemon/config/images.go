@@ -0,0 +1,71 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Default values of the image GC config
+const (
+	DefaultImageGCHighWatermark = 85
+	DefaultImageGCLowWatermark  = 70
+	DefaultImageGCInterval      = 5 * time.Minute
+)
+
+// ImageGCConfig contains the config of the automatic garbage collection of
+// the unused images
+type ImageGCConfig struct {
+	Enabled       bool     `json:",omitempty"`
+	HighWatermark int      `json:",omitempty"` // HighWatermark is the usage of the disk of the data-root, in percent, above which images are collected
+	LowWatermark  int      `json:",omitempty"` // LowWatermark is the usage of the disk of the data-root, in percent, down to which images are collected
+	Interval      string   `json:",omitempty"` // Interval is the interval between two checks of the usage of the disk, e.g. "5m"
+	ExcludeLabels []string `json:",omitempty"` // ExcludeLabels are the labels, "key" or "key=value", of the images which are never collected
+}
+
+// Watermarks returns the high and low watermarks of the image GC, with their
+// default values if unset.
+func (c ImageGCConfig) Watermarks() (high, low int) {
+	high, low = c.HighWatermark, c.LowWatermark
+	if high == 0 {
+		high = DefaultImageGCHighWatermark
+	}
+	if low == 0 {
+		low = DefaultImageGCLowWatermark
+	}
+	return high, low
+}
+
+// GetInterval returns the interval of the image GC, or its default value if
+// unset.
+func (c ImageGCConfig) GetInterval() time.Duration {
+	if d, err := time.ParseDuration(c.Interval); err == nil && d > 0 {
+		return d
+	}
+	return DefaultImageGCInterval
+}
+
+func (c ImageGCConfig) validate() error {
+	high, low := c.Watermarks()
+	if high <= 0 || high > 100 || low <= 0 || low > 100 {
+		return fmt.Errorf("invalid image GC watermarks: must be between 1 and 100")
+	}
+	if low >= high {
+		return fmt.Errorf("invalid image GC watermarks: the low watermark (%d) must be lower than the high watermark (%d)", low, high)
+	}
+	if c.Interval != "" {
+		d, err := time.ParseDuration(c.Interval)
+		if err != nil {
+			return fmt.Errorf("invalid image GC interval: %v", err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("invalid image GC interval: %s", c.Interval)
+		}
+	}
+	for _, l := range c.ExcludeLabels {
+		if strings.SplitN(l, "=", 2)[0] == "" {
+			return fmt.Errorf("invalid image GC exclude label: %s", l)
+		}
+	}
+	return nil
+}
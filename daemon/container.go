@@ -268,7 +268,10 @@ func validateContainerConfig(config *containertypes.Config, platform string) err
 			return err
 		}
 	}
-	return validateHealthCheck(config.Healthcheck)
+	if err := validateHealthCheck(config.Healthcheck); err != nil {
+		return err
+	}
+	return errors.Wrap(validateHealthCheck(config.Readinesscheck), "invalid Readinesscheck")
 }
 
 func validateHostConfig(hostConfig *containertypes.HostConfig, platform string) error {
@@ -342,6 +345,18 @@ func validateHealthCheck(healthConfig *containertypes.HealthConfig) error {
 	if healthConfig.StartPeriod != 0 && healthConfig.StartPeriod < containertypes.MinimumDuration {
 		return errors.Errorf("StartPeriod in Healthcheck cannot be less than %s", containertypes.MinimumDuration)
 	}
+	if len(healthConfig.Test) > 0 {
+		switch healthConfig.Test[0] {
+		case "HTTP":
+			if _, err := newHTTPProbe(healthConfig.Test[1:]); err != nil {
+				return err
+			}
+		case "TCP":
+			if _, err := newTCPProbe(healthConfig.Test[1:]); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -367,7 +382,7 @@ func validateRestartPolicy(policy containertypes.RestartPolicy) error {
 		if policy.MaximumRetryCount != 0 {
 			return errors.Errorf("maximum retry count cannot be used with restart policy '%s'", policy.Name)
 		}
-	case "on-failure":
+	case "on-failure", "on-unhealthy":
 		if policy.MaximumRetryCount < 0 {
 			return errors.Errorf("maximum retry count cannot be negative")
 		}
@@ -377,6 +392,26 @@ func validateRestartPolicy(policy containertypes.RestartPolicy) error {
 	default:
 		return errors.Errorf("invalid restart policy '%s'", policy.Name)
 	}
+	if policy.InitialDelay < 0 || policy.MaxDelay < 0 || policy.ResetWindow < 0 || policy.CrashLoopWindow < 0 {
+		return errors.Errorf("restart policy delays and windows cannot be negative")
+	}
+	if policy.InitialDelay > 0 && policy.MaxDelay > 0 && policy.InitialDelay > policy.MaxDelay {
+		return errors.Errorf("restart policy initial delay cannot be greater than the maximum delay")
+	}
+	if policy.Multiplier != 0 && policy.Multiplier < 1 {
+		return errors.Errorf("restart policy multiplier cannot be less than 1")
+	}
+	if policy.CrashLoopThreshold < 0 {
+		return errors.Errorf("restart policy crash-loop threshold cannot be negative")
+	}
+	if policy.UnhealthyTimeout != 0 {
+		if !policy.IsOnUnhealthy() {
+			return errors.Errorf("unhealthy timeout cannot be used with restart policy '%s'", policy.Name)
+		}
+		if policy.UnhealthyTimeout < 0 {
+			return errors.Errorf("restart policy unhealthy timeout cannot be negative")
+		}
+	}
 	return nil
 }
 
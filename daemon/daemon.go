@@ -91,6 +91,8 @@ type Daemon struct {
 	idIndex           *truncindex.TruncIndex
 	configStore       *config.Config
 	statsCollector    *stats.Collector
+	statsHistory      *stats.History
+	trackStats        bool // collect the stats of all running containers
 	defaultLogConfig  containertypes.LogConfig
 	RegistryService   registry.Service
 	EventsService     *events.Events
@@ -1028,8 +1030,35 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 	d.execCommands = exec.NewStore()
 	d.idIndex = truncindex.NewTruncIndex([]string{})
 	d.statsCollector = d.newStatsCollector(1 * time.Second)
+	d.statsHistory = stats.NewHistory(stats.DefaultHistoryTiers)
+	d.statsCollector.AddObserver(d.statsHistory)
+	d.trackStats = true
+	if config.MetricsAddress != "" {
+		if err := d.registerContainerMetrics(config.MetricsContainerLabels); err != nil {
+			return nil, err
+		}
+	}
 
 	d.EventsService = events.New()
+	if config.Events.Journal.Enabled {
+		journal, err := newEventsJournal(filepath.Join(config.Root, "events"), config.Events.Journal)
+		if err != nil {
+			return nil, err
+		}
+		d.EventsService.SetJournal(journal)
+	}
+	sinkNames := make(map[string]struct{}, len(config.Events.Sinks))
+	for _, sinkConfig := range config.Events.Sinks {
+		if _, ok := sinkNames[sinkConfig.Name]; ok {
+			return nil, errors.Errorf("duplicate event sink name %q", sinkConfig.Name)
+		}
+		sinkNames[sinkConfig.Name] = struct{}{}
+		sink, err := newEventSink(filepath.Join(config.Root, "events", "sinks"), sinkConfig)
+		if err != nil {
+			return nil, err
+		}
+		d.EventsService.AddSink(sink)
+	}
 	d.root = config.Root
 	d.idMapping = idMapping
 	d.seccompEnabled = sysInfo.Seccomp
@@ -1055,6 +1084,17 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 
 	go d.execCommandGC()
 
+	if gc := config.ImageGC; gc.Enabled {
+		high, low := gc.Watermarks()
+		go d.imageService.RunGC(images.GCPolicy{
+			Root:          config.Root,
+			HighWatermark: high,
+			LowWatermark:  low,
+			Interval:      gc.GetInterval(),
+			ExcludeLabels: gc.ExcludeLabels,
+		})
+	}
+
 	d.containerd, err = libcontainerd.NewClient(ctx, d.containerdCli, filepath.Join(config.ExecRoot, "containerd"), config.ContainerdNamespace, d)
 	if err != nil {
 		return nil, err
@@ -1217,6 +1257,12 @@ func (daemon *Daemon) Shutdown() error {
 		daemon.containerdCli.Close()
 	}
 
+	if daemon.EventsService != nil {
+		if err := daemon.EventsService.Close(); err != nil {
+			logrus.Errorf("Error closing event journal: %v", err)
+		}
+	}
+
 	return daemon.cleanupMounts()
 }
 
@@ -1471,6 +1471,10 @@ func (daemon *Daemon) stats(c *container.Container) (*types.StatsJSON, error) {
 		}
 	}
 
+	if err := cgroupStats(c, s); err != nil {
+		logrus.WithError(err).WithField("container", c.ID).Debug("Error reading cgroup stats")
+	}
+
 	return s, nil
 }
 
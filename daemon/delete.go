@@ -146,6 +146,9 @@ func (daemon *Daemon) cleanupContainer(container *container.Container, forceRemo
 	}
 	container.SetRemoved()
 	stateCtr.del(container.ID)
+	if daemon.statsHistory != nil {
+		daemon.statsHistory.Remove(container.ID)
+	}
 
 	daemon.LogContainerEvent(container, "destroy")
 	return nil
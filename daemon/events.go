@@ -2,6 +2,7 @@ package daemon // import "github.com/docker/docker/daemon"
 
 import (
 	"context"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -9,10 +10,14 @@ import (
 	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/config"
 	daemonevents "github.com/docker/docker/daemon/events"
+	"github.com/docker/docker/daemon/names"
+	"github.com/docker/go-units"
 	"github.com/docker/libnetwork"
 	swarmapi "github.com/docker/swarmkit/api"
 	gogotypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
@@ -110,6 +115,57 @@ func (daemon *Daemon) UnsubscribeFromEvents(listener chan interface{}) {
 	daemon.EventsService.Evict(listener)
 }
 
+// newEventsJournal opens the on-disk event journal stored in root.
+func newEventsJournal(root string, cfg config.EventsJournalConfig) (*daemonevents.Journal, error) {
+	var (
+		maxSize int64
+		maxAge  time.Duration
+		err     error
+	)
+	if cfg.MaxSize != "" {
+		if maxSize, err = units.RAMInBytes(cfg.MaxSize); err != nil {
+			return nil, errors.Wrap(err, "invalid event journal max-size")
+		}
+	}
+	if cfg.MaxAge != "" {
+		if maxAge, err = time.ParseDuration(cfg.MaxAge); err != nil {
+			return nil, errors.Wrap(err, "invalid event journal max-age")
+		}
+	}
+	return daemonevents.NewJournal(root, maxSize, maxAge)
+}
+
+// newEventSink creates the event sink described by cfg, spooling the events
+// it fails to deliver in root.
+func newEventSink(root string, cfg config.EventSinkConfig) (*daemonevents.Sink, error) {
+	if !names.RestrictedNamePattern.MatchString(cfg.Name) {
+		return nil, errors.Errorf("invalid event sink name %q: only %s are allowed", cfg.Name, names.RestrictedNameChars)
+	}
+	opts := daemonevents.SinkOptions{
+		Name:       cfg.Name,
+		Type:       cfg.Type,
+		Address:    cfg.Address,
+		BatchSize:  cfg.BatchSize,
+		MaxRetries: cfg.MaxRetries,
+		SpoolDir:   filepath.Join(root, cfg.Name),
+	}
+	if f := filters.Args(cfg.Filter); f.Len() > 0 {
+		opts.Filter = daemonevents.NewFilter(f)
+	}
+	var err error
+	if cfg.BatchTimeout != "" {
+		if opts.BatchTimeout, err = time.ParseDuration(cfg.BatchTimeout); err != nil {
+			return nil, errors.Wrapf(err, "invalid batch timeout for event sink %s", cfg.Name)
+		}
+	}
+	if cfg.SpoolSize != "" {
+		if opts.SpoolSize, err = units.RAMInBytes(cfg.SpoolSize); err != nil {
+			return nil, errors.Wrapf(err, "invalid spool size for event sink %s", cfg.Name)
+		}
+	}
+	return daemonevents.NewSink(opts)
+}
+
 // copyAttributes guarantees that labels are not mutated by event triggers.
 func copyAttributes(attributes, labels map[string]string) {
 	if labels == nil {
@@ -6,6 +6,7 @@ import (
 
 	eventtypes "github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/pkg/pubsub"
+	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -15,9 +16,11 @@ const (
 
 // Events is pubsub channel for events generated by the engine.
 type Events struct {
-	mu     sync.Mutex
-	events []eventtypes.Message
-	pub    *pubsub.Publisher
+	mu      sync.Mutex
+	events  []eventtypes.Message
+	pub     *pubsub.Publisher
+	journal *Journal
+	sinks   []*Sink
 }
 
 // New returns new *Events instance
@@ -28,6 +31,37 @@ func New() *Events {
 	}
 }
 
+// SetJournal makes e persist all events to j, and replay events from it when
+// they are no longer in the in-memory buffer.
+func (e *Events) SetJournal(j *Journal) {
+	e.mu.Lock()
+	e.journal = j
+	e.mu.Unlock()
+}
+
+// AddSink makes e forward all events to s.
+func (e *Events) AddSink(s *Sink) {
+	e.mu.Lock()
+	e.sinks = append(e.sinks, s)
+	e.mu.Unlock()
+}
+
+// Close closes the event sinks and the event journal, if any.
+func (e *Events) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, s := range e.sinks {
+		if err := s.Close(); err != nil {
+			logrus.WithError(err).WithField("sink", s.opts.Name).Warn("failed to close event sink")
+		}
+	}
+	e.sinks = nil
+	if e.journal == nil {
+		return nil
+	}
+	return e.journal.Close()
+}
+
 // Subscribe adds new listener to events, returns slice of 256 stored
 // last events, a channel in which you can expect new events (in form
 // of interface{}, so you need type assertion), and a function to call
@@ -49,6 +83,8 @@ func (e *Events) Subscribe() ([]eventtypes.Message, chan interface{}, func()) {
 // SubscribeTopic adds new listener to events, returns slice of 256 stored
 // last events, a channel in which you can expect new events (in form
 // of interface{}, so you need type assertion).
+// If an event journal is set, and the requested events are no longer in the
+// in-memory buffer, they are replayed from the journal instead.
 func (e *Events) SubscribeTopic(since, until time.Time, ef *Filter) ([]eventtypes.Message, chan interface{}) {
 	eventSubscribers.Inc()
 	e.mu.Lock()
@@ -58,7 +94,18 @@ func (e *Events) SubscribeTopic(since, until time.Time, ef *Filter) ([]eventtype
 		topic = func(m interface{}) bool { return ef.Include(m.(eventtypes.Message)) }
 	}
 
-	buffered := e.loadBufferedEvents(since, until, topic)
+	var (
+		buffered   []eventtypes.Message
+		journal    *Journal
+		journalPos journalPosition
+	)
+	if e.journal != nil && !(since.IsZero() && until.IsZero()) && !e.bufferCovers(since) {
+		// Replay from the journal up to the current position, which is
+		// where the subscription below picks up.
+		journal, journalPos = e.journal, e.journal.position()
+	} else {
+		buffered = e.loadBufferedEvents(since, until, topic)
+	}
 
 	var ch chan interface{}
 	if topic != nil {
@@ -69,9 +116,23 @@ func (e *Events) SubscribeTopic(since, until time.Time, ef *Filter) ([]eventtype
 	}
 
 	e.mu.Unlock()
+
+	if journal != nil {
+		var err error
+		buffered, err = journal.read(since, until, journalPos, topic)
+		if err != nil {
+			logrus.WithError(err).Warn("failed to replay events from the event journal")
+		}
+	}
 	return buffered, ch
 }
 
+// bufferCovers returns true if all events emitted since the given time are
+// still in the in-memory buffer.
+func (e *Events) bufferCovers(since time.Time) bool {
+	return len(e.events) > 0 && !since.IsZero() && e.events[0].TimeNano <= since.UnixNano()
+}
+
 // Evict evicts listener from pubsub
 func (e *Events) Evict(l chan interface{}) {
 	eventSubscribers.Dec()
@@ -110,6 +171,14 @@ func (e *Events) PublishMessage(jm eventtypes.Message) {
 	eventsCounter.Inc()
 
 	e.mu.Lock()
+	if e.journal != nil {
+		if err := e.journal.Write(jm); err != nil {
+			logrus.WithError(err).Warn("failed to write event to the event journal")
+		}
+	}
+	for _, s := range e.sinks {
+		s.Publish(jm)
+	}
 	if len(e.events) == cap(e.events) {
 		// discard oldest event
 		copy(e.events, e.events[1:])
@@ -1,6 +1,8 @@
 package events // import "github.com/docker/docker/daemon/events"
 
 import (
+	"strings"
+
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
@@ -8,12 +10,28 @@ import (
 
 // Filter can filter out docker events from a stream
 type Filter struct {
-	filter filters.Args
+	filter  filters.Args
+	negated []*Filter // events included by any of these are filtered out
 }
 
-// NewFilter creates a new Filter
+// NewFilter creates a new Filter.
+//
+// Filters whose key ends with "!", such as "event!=start" or
+// "container!=web", are negated: events they would include are filtered
+// out instead.
 func NewFilter(filter filters.Args) *Filter {
-	return &Filter{filter: filter}
+	ef := &Filter{filter: filter}
+	for _, key := range filter.Keys() {
+		if key == "!" || !strings.HasSuffix(key, "!") {
+			continue
+		}
+		for _, value := range filter.Get(key) {
+			ef.negated = append(ef.negated, &Filter{
+				filter: filters.NewArgs(filters.Arg(strings.TrimSuffix(key, "!"), value)),
+			})
+		}
+	}
+	return ef
 }
 
 // Include returns true when the event ev is included by the filters
@@ -31,7 +49,18 @@ func (ef *Filter) Include(ev events.Message) bool {
 		ef.matchService(ev) &&
 		ef.matchSecret(ev) &&
 		ef.matchConfig(ev) &&
-		ef.matchLabels(ev.Actor.Attributes)
+		ef.matchLabels(ev.Actor.Attributes) &&
+		ef.filter.MatchAttrs("attr", ev.Actor.Attributes) &&
+		!ef.matchNegated(ev)
+}
+
+func (ef *Filter) matchNegated(ev events.Message) bool {
+	for _, nf := range ef.negated {
+		if nf.Include(ev) {
+			return true
+		}
+	}
+	return false
 }
 
 func (ef *Filter) matchEvent(ev events.Message) bool {
@@ -104,7 +133,8 @@ func (ef *Filter) matchConfig(ev events.Message) bool {
 
 func (ef *Filter) fuzzyMatchName(ev events.Message, eventType string) bool {
 	return ef.filter.FuzzyMatch(eventType, ev.Actor.ID) ||
-		ef.filter.FuzzyMatch(eventType, ev.Actor.Attributes["name"])
+		ef.filter.FuzzyMatch(eventType, ev.Actor.Attributes["name"]) ||
+		ef.filter.GlobMatch(eventType, ev.Actor.Attributes["name"])
 }
 
 // matchImage matches against both event.Actor.ID (for image events)
@@ -0,0 +1,104 @@
+package events // import "github.com/docker/docker/daemon/events"
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+func TestFilterInclude(t *testing.T) {
+	die := events.Message{
+		Type:   events.ContainerEventType,
+		Action: "die",
+		Actor: events.Actor{
+			ID:         "0123456789ab",
+			Attributes: map[string]string{"name": "web-1", "image": "nginx", "exitCode": "137", "signal": "9"},
+		},
+	}
+	start := events.Message{
+		Type:   events.ContainerEventType,
+		Action: "start",
+		Actor: events.Actor{
+			ID:         "0123456789ab",
+			Attributes: map[string]string{"name": "web-1", "image": "nginx"},
+		},
+	}
+	pull := events.Message{
+		Type:   events.ImageEventType,
+		Action: "pull",
+		Actor: events.Actor{
+			ID:         "nginx:latest",
+			Attributes: map[string]string{"name": "nginx"},
+		},
+	}
+
+	cases := []struct {
+		name    string
+		filter  filters.Args
+		include []events.Message
+		exclude []events.Message
+	}{
+		{
+			name:    "attr numeric comparison",
+			filter:  filters.NewArgs(filters.Arg("attr", "exitCode>0")),
+			include: []events.Message{die},
+			exclude: []events.Message{start, pull},
+		},
+		{
+			name:    "attr range",
+			filter:  filters.NewArgs(filters.Arg("attr", "exitCode>=128"), filters.Arg("attr", "exitCode<=255")),
+			include: []events.Message{die},
+			exclude: []events.Message{start},
+		},
+		{
+			name:    "attr equality",
+			filter:  filters.NewArgs(filters.Arg("attr", "signal=9")),
+			include: []events.Message{die},
+			exclude: []events.Message{start},
+		},
+		{
+			name:    "negated event",
+			filter:  filters.NewArgs(filters.Arg("type", events.ContainerEventType), filters.Arg("event!", "start")),
+			include: []events.Message{die},
+			exclude: []events.Message{start, pull},
+		},
+		{
+			name:    "negated type",
+			filter:  filters.NewArgs(filters.Arg("type!", events.ImageEventType)),
+			include: []events.Message{die, start},
+			exclude: []events.Message{pull},
+		},
+		{
+			name:    "negated attr",
+			filter:  filters.NewArgs(filters.Arg("attr!", "exitCode")),
+			include: []events.Message{start, pull},
+			exclude: []events.Message{die},
+		},
+		{
+			name:    "glob name",
+			filter:  filters.NewArgs(filters.Arg("container", "*-1")),
+			include: []events.Message{die, start},
+			exclude: []events.Message{pull},
+		},
+		{
+			name:    "negated glob name",
+			filter:  filters.NewArgs(filters.Arg("container!", "web-*")),
+			include: []events.Message{pull},
+			exclude: []events.Message{die, start},
+		},
+	}
+	for _, tc := range cases {
+		ef := NewFilter(tc.filter)
+		for _, ev := range tc.include {
+			if !ef.Include(ev) {
+				t.Errorf("%s: expected %s %s to be included", tc.name, ev.Type, ev.Action)
+			}
+		}
+		for _, ev := range tc.exclude {
+			if ef.Include(ev) {
+				t.Errorf("%s: expected %s %s to be excluded", tc.name, ev.Type, ev.Action)
+			}
+		}
+	}
+}
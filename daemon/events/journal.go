@@ -0,0 +1,282 @@
+package events // import "github.com/docker/docker/daemon/events"
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultJournalMaxSize is the default maximum size of the event journal.
+	DefaultJournalMaxSize = 64 * 1024 * 1024
+	// DefaultJournalMaxAge is the default maximum age of the events kept in
+	// the event journal.
+	DefaultJournalMaxAge = 24 * time.Hour
+
+	// journalSegments is the number of segments the journal is split into.
+	// Retention drops whole segments, so it's the granularity at which old
+	// events are discarded.
+	journalSegments = 4
+	journalExt      = ".log"
+)
+
+// Journal is an on-disk log of events with size and age based retention.
+//
+// Events are appended as JSON lines to segment files, which are named after
+// the time of their first event so that segments can be skipped when events
+// are replayed.
+type Journal struct {
+	mu       sync.Mutex
+	root     string
+	maxSize  int64
+	maxAge   time.Duration
+	segments []*journalSegment // oldest first, the last one is written to
+	f        *os.File          // file of the last segment, nil until the first write
+}
+
+type journalSegment struct {
+	path  string
+	start int64     // TimeNano of the first event
+	last  time.Time // time of the last event
+	size  int64
+}
+
+// journalPosition is the end of the journal at a given point in time.
+type journalPosition struct {
+	start  int64 // start of the last segment
+	offset int64 // size of the last segment
+}
+
+// NewJournal opens the event journal stored in root, creating it if needed.
+// A maxSize or maxAge of 0 means the default.
+func NewJournal(root string, maxSize int64, maxAge time.Duration) (*Journal, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultJournalMaxSize
+	}
+	if maxAge <= 0 {
+		maxAge = DefaultJournalMaxAge
+	}
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create event journal directory")
+	}
+	fis, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read event journal directory")
+	}
+
+	j := &Journal{root: root, maxSize: maxSize, maxAge: maxAge}
+	for _, fi := range fis {
+		if fi.IsDir() || !strings.HasSuffix(fi.Name(), journalExt) {
+			continue
+		}
+		start, err := strconv.ParseInt(strings.TrimSuffix(fi.Name(), journalExt), 10, 64)
+		if err != nil {
+			logrus.WithField("file", fi.Name()).Warn("ignoring unexpected file in event journal directory")
+			continue
+		}
+		j.segments = append(j.segments, &journalSegment{
+			path:  filepath.Join(root, fi.Name()),
+			start: start,
+			last:  fi.ModTime(),
+			size:  fi.Size(),
+		})
+	}
+	sort.Slice(j.segments, func(i, k int) bool { return j.segments[i].start < j.segments[k].start })
+
+	j.mu.Lock()
+	j.prune(time.Now())
+	j.mu.Unlock()
+	return j, nil
+}
+
+// Write appends an event to the journal.
+func (j *Journal) Write(ev eventtypes.Message) error {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.shouldRotate(ev, int64(len(b))) {
+		if err := j.rotate(ev.TimeNano); err != nil {
+			return err
+		}
+	}
+	seg := j.segments[len(j.segments)-1]
+	n, err := j.f.Write(b)
+	seg.size += int64(n)
+	seg.last = time.Unix(0, ev.TimeNano)
+	return err
+}
+
+// shouldRotate returns true if ev has to be written to a new segment, either
+// because there is no current segment, or because the current segment is
+// full or spans its share of the maximum age.
+func (j *Journal) shouldRotate(ev eventtypes.Message, size int64) bool {
+	if j.f == nil {
+		return true
+	}
+	seg := j.segments[len(j.segments)-1]
+	if seg.size > 0 && seg.size+size > j.maxSize/journalSegments {
+		return true
+	}
+	return time.Duration(ev.TimeNano-seg.start) > j.maxAge/journalSegments
+}
+
+// rotate starts a new segment beginning at start, and drops the segments
+// that fell out of retention.
+func (j *Journal) rotate(start int64) error {
+	if j.f != nil {
+		if err := j.f.Close(); err != nil {
+			logrus.WithError(err).Warn("failed to close event journal segment")
+		}
+		j.f = nil
+	}
+	if n := len(j.segments); n > 0 && j.segments[n-1].start >= start {
+		// keep segments ordered, even if the clock went backwards
+		start = j.segments[n-1].start + 1
+	}
+
+	path := filepath.Join(j.root, strconv.FormatInt(start, 10)+journalExt)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create event journal segment")
+	}
+	j.f = f
+	j.segments = append(j.segments, &journalSegment{path: path, start: start, last: time.Unix(0, start)})
+	j.prune(time.Now())
+	return nil
+}
+
+// prune removes the oldest segments while the journal is larger than its
+// maximum size, or their events are older than its maximum age. The segment
+// being written to is never removed.
+func (j *Journal) prune(now time.Time) {
+	var size int64
+	for _, seg := range j.segments {
+		size += seg.size
+	}
+	for len(j.segments) > 1 || (len(j.segments) == 1 && j.f == nil) {
+		seg := j.segments[0]
+		if size <= j.maxSize && now.Sub(seg.last) <= j.maxAge {
+			break
+		}
+		if err := os.Remove(seg.path); err != nil && !os.IsNotExist(err) {
+			logrus.WithError(err).WithField("file", seg.path).Warn("failed to remove event journal segment")
+			break
+		}
+		size -= seg.size
+		j.segments = j.segments[1:]
+	}
+}
+
+// position returns the current end of the journal.
+func (j *Journal) position() journalPosition {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if len(j.segments) == 0 {
+		return journalPosition{start: -1}
+	}
+	seg := j.segments[len(j.segments)-1]
+	return journalPosition{start: seg.start, offset: seg.size}
+}
+
+// read returns the events written to the journal before pos that were
+// emitted between since and until, filtered by topic if it's not nil.
+func (j *Journal) read(since, until time.Time, pos journalPosition, topic func(interface{}) bool) ([]eventtypes.Message, error) {
+	j.mu.Lock()
+	segments := make([]journalSegment, 0, len(j.segments))
+	for _, seg := range j.segments {
+		segments = append(segments, *seg)
+	}
+	j.mu.Unlock()
+
+	var sinceNanoUnix, untilNanoUnix int64
+	if !since.IsZero() {
+		sinceNanoUnix = since.UnixNano()
+	}
+	if !until.IsZero() {
+		untilNanoUnix = until.UnixNano()
+	}
+
+	var evs []eventtypes.Message
+	for i, seg := range segments {
+		if seg.start > pos.start || (untilNanoUnix > 0 && seg.start > untilNanoUnix) {
+			break
+		}
+		if i+1 < len(segments) && segments[i+1].start <= sinceNanoUnix {
+			// all events of this segment were emitted before since
+			continue
+		}
+		limit := seg.size
+		if seg.start == pos.start {
+			limit = pos.offset
+		}
+		if err := readSegment(seg.path, limit, func(ev eventtypes.Message) {
+			if ev.TimeNano < sinceNanoUnix || (untilNanoUnix > 0 && ev.TimeNano > untilNanoUnix) {
+				return
+			}
+			if topic == nil || topic(ev) {
+				evs = append(evs, ev)
+			}
+		}); err != nil {
+			return evs, err
+		}
+	}
+	return evs, nil
+}
+
+// readSegment decodes the first limit bytes of the segment at path, and
+// calls fn for each event.
+func readSegment(path string, limit int64, fn func(eventtypes.Message)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// removed by retention in the meantime
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(io.LimitReader(f, limit))
+	for {
+		var ev eventtypes.Message
+		if err := dec.Decode(&ev); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			// A truncated event at the end of a segment written before a
+			// crash; skip the rest of the segment.
+			logrus.WithError(err).WithField("file", path).Warn("failed to decode event from journal")
+			return nil
+		}
+		fn(ev)
+	}
+}
+
+// Close closes the journal.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f == nil {
+		return nil
+	}
+	err := j.f.Close()
+	j.f = nil
+	return err
+}
@@ -0,0 +1,158 @@
+package events // import "github.com/docker/docker/daemon/events"
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+)
+
+func TestJournalReopen(t *testing.T) {
+	root, err := ioutil.TempDir("", "events-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	j, err := NewJournal(root, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		ev := events.Message{
+			Type:     events.ContainerEventType,
+			Action:   "start",
+			Actor:    events.Actor{ID: strconv.Itoa(i)},
+			Time:     now.Add(time.Duration(i) * time.Second).Unix(),
+			TimeNano: now.Add(time.Duration(i) * time.Second).UnixNano(),
+		}
+		if err := j.Write(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err = NewJournal(root, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	evs, err := j.read(now.Add(5*time.Second), time.Time{}, j.position(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 5 {
+		t.Fatalf("expected 5 events, got %d", len(evs))
+	}
+	if evs[0].Actor.ID != "5" || evs[4].Actor.ID != "9" {
+		t.Fatalf("unexpected events: %v", evs)
+	}
+
+	evs, err = j.read(now, now.Add(2*time.Second), j.position(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(evs))
+	}
+}
+
+func TestJournalRetention(t *testing.T) {
+	root, err := ioutil.TempDir("", "events-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	const maxSize = 4096
+	j, err := NewJournal(root, maxSize, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	now := time.Now()
+	for i := 0; i < 1000; i++ {
+		ev := events.Message{
+			Type:     events.ContainerEventType,
+			Action:   "start",
+			Actor:    events.Actor{ID: strconv.Itoa(i)},
+			TimeNano: now.Add(time.Duration(i) * time.Millisecond).UnixNano(),
+		}
+		if err := j.Write(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var size int64
+	for _, seg := range j.segments {
+		size += seg.size
+	}
+	if size > maxSize {
+		t.Fatalf("expected journal to be at most %d bytes, got %d", maxSize, size)
+	}
+	fis, err := ioutil.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fis) != len(j.segments) {
+		t.Fatalf("expected %d segment files, got %d", len(j.segments), len(fis))
+	}
+
+	evs, err := j.read(now, time.Time{}, j.position(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evs) == 0 || evs[len(evs)-1].Actor.ID != "999" {
+		t.Fatalf("expected the most recent events to be kept, got %v", evs)
+	}
+}
+
+func TestSubscribeTopicReplaysJournal(t *testing.T) {
+	root, err := ioutil.TempDir("", "events-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	j, err := NewJournal(root, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := New()
+	e.SetJournal(j)
+	since := time.Now()
+	e.Log("create", events.ContainerEventType, events.Actor{ID: "c1"})
+	e.Log("start", events.ContainerEventType, events.Actor{ID: "c1"})
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate a daemon restart
+	j, err = NewJournal(root, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e = New()
+	e.SetJournal(j)
+	defer e.Close()
+	e.Log("die", events.ContainerEventType, events.Actor{ID: "c1"})
+
+	buffered, l := e.SubscribeTopic(since, time.Time{}, nil)
+	defer e.Evict(l)
+	if len(buffered) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(buffered))
+	}
+	for i, action := range []string{"create", "start", "die"} {
+		if buffered[i].Action != action {
+			t.Fatalf("expected event %d to be %q, got %q", i, action, buffered[i].Action)
+		}
+	}
+}
@@ -0,0 +1,443 @@
+package events // import "github.com/docker/docker/daemon/events"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Types of event sinks
+const (
+	// SinkTypeWebhook posts batches of events as a JSON array to an HTTP
+	// endpoint.
+	SinkTypeWebhook = "webhook"
+	// SinkTypeUnix writes events as JSON lines to a unix stream socket.
+	SinkTypeUnix = "unix"
+	// SinkTypeUnixgram sends each event as a JSON datagram to a unix
+	// datagram socket.
+	SinkTypeUnixgram = "unixgram"
+)
+
+const (
+	defaultSinkBatchSize    = 100
+	defaultSinkBatchTimeout = time.Second
+	defaultSinkMaxRetries   = 3
+	defaultSinkSpoolSize    = 16 * 1024 * 1024
+	sinkQueueSize           = 1024
+	sinkTimeout             = 10 * time.Second
+	sinkMaxRetryDelay       = 30 * time.Second
+)
+
+// sinkRetryDelay is the delay before the first retry of a failed delivery.
+// It doubles for every subsequent retry.
+var sinkRetryDelay = 500 * time.Millisecond
+
+// SinkOptions is the configuration of an event sink.
+type SinkOptions struct {
+	Name    string
+	Type    string // one of SinkTypeWebhook, SinkTypeUnix, or SinkTypeUnixgram
+	Address string // URL of the webhook, or path of the socket
+	Filter  *Filter
+
+	// BatchSize is the maximum number of events delivered at once, and
+	// BatchTimeout the maximum time an event waits for its batch to fill.
+	BatchSize    int
+	BatchTimeout time.Duration
+	// MaxRetries is the number of times delivering a batch is retried
+	// before it is spooled.
+	MaxRetries int
+
+	// SpoolDir is where the events that could not be delivered are kept
+	// until the sink is reachable again, up to SpoolSize bytes.
+	SpoolDir  string
+	SpoolSize int64
+}
+
+// Sink forwards events to an external endpoint.
+//
+// Events are delivered in batches. Batches that cannot be delivered are
+// stored in an on-disk spool, and delivered once the endpoint is reachable
+// again, including after a daemon restart. When the spool is full, the
+// oldest events are dropped.
+type Sink struct {
+	opts    SinkOptions
+	deliver func(context.Context, []eventtypes.Message) error
+	spool   *sinkSpool
+	queue   chan eventtypes.Message
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSink creates a sink with the given options, and starts delivering
+// events to it, starting with the events left in its spool.
+func NewSink(opts SinkOptions) (*Sink, error) {
+	if opts.Name == "" {
+		return nil, errors.New("event sink name is required")
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultSinkBatchSize
+	}
+	if opts.BatchTimeout <= 0 {
+		opts.BatchTimeout = defaultSinkBatchTimeout
+	}
+	if opts.MaxRetries <= 0 {
+		opts.MaxRetries = defaultSinkMaxRetries
+	}
+	if opts.SpoolSize <= 0 {
+		opts.SpoolSize = defaultSinkSpoolSize
+	}
+
+	s := &Sink{opts: opts}
+	switch opts.Type {
+	case SinkTypeWebhook:
+		u, err := url.Parse(opts.Address)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, errors.Errorf("invalid address for event sink %s: webhook address must be an http or https URL", opts.Name)
+		}
+		client := &http.Client{Timeout: sinkTimeout}
+		s.deliver = func(ctx context.Context, evs []eventtypes.Message) error {
+			return postEvents(ctx, client, opts.Address, evs)
+		}
+	case SinkTypeUnix, SinkTypeUnixgram:
+		if !filepath.IsAbs(opts.Address) {
+			return nil, errors.Errorf("invalid address for event sink %s: socket path must be absolute", opts.Name)
+		}
+		s.deliver = func(ctx context.Context, evs []eventtypes.Message) error {
+			return writeEvents(ctx, opts.Type, opts.Address, evs)
+		}
+	default:
+		return nil, errors.Errorf("invalid type for event sink %s: %q", opts.Name, opts.Type)
+	}
+
+	spool, err := newSinkSpool(opts.SpoolDir, opts.SpoolSize)
+	if err != nil {
+		return nil, err
+	}
+	s.spool = spool
+	s.queue = make(chan eventtypes.Message, sinkQueueSize)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan struct{})
+	go s.run()
+	return s, nil
+}
+
+// Publish queues ev for delivery if it matches the filter of the sink. It
+// never blocks: if the sink falls behind, the event is spooled instead.
+func (s *Sink) Publish(ev eventtypes.Message) {
+	if s.opts.Filter != nil && !s.opts.Filter.Include(ev) {
+		return
+	}
+	select {
+	case s.queue <- ev:
+	default:
+		s.spoolEvents([]eventtypes.Message{ev})
+	}
+}
+
+// Close stops the sink. Queued events which were not delivered yet are
+// spooled, to be delivered when the sink is created again.
+func (s *Sink) Close() error {
+	s.cancel()
+	<-s.done
+	return nil
+}
+
+func (s *Sink) run() {
+	defer close(s.done)
+
+	timer := time.NewTimer(s.opts.BatchTimeout)
+	defer timer.Stop()
+
+	var batch []eventtypes.Message
+	for {
+		select {
+		case ev := <-s.queue:
+			batch = append(batch, ev)
+			if len(batch) < s.opts.BatchSize {
+				continue
+			}
+			s.flush(batch)
+			batch = nil
+		case <-timer.C:
+			s.flush(batch)
+			batch = nil
+			timer.Reset(s.opts.BatchTimeout)
+		case <-s.ctx.Done():
+			s.spoolEvents(append(batch, s.drainQueue()...))
+			return
+		}
+	}
+}
+
+func (s *Sink) drainQueue() []eventtypes.Message {
+	var evs []eventtypes.Message
+	for {
+		select {
+		case ev := <-s.queue:
+			evs = append(evs, ev)
+		default:
+			return evs
+		}
+	}
+}
+
+// flush delivers the spooled events, and then batch. If the sink is not
+// reachable, batch is added to the spool.
+func (s *Sink) flush(batch []eventtypes.Message) {
+	for {
+		name, spooled, err := s.spool.oldest()
+		if err != nil {
+			logrus.WithError(err).WithField("sink", s.opts.Name).Warn("failed to read spooled events")
+			continue
+		}
+		if name == "" {
+			break
+		}
+		if err := s.deliverWithRetries(spooled); err != nil {
+			s.spoolEvents(batch)
+			return
+		}
+		s.spool.remove(name)
+	}
+	if len(batch) == 0 {
+		return
+	}
+	if err := s.deliverWithRetries(batch); err != nil {
+		s.spoolEvents(batch)
+	}
+}
+
+func (s *Sink) deliverWithRetries(evs []eventtypes.Message) error {
+	if len(evs) == 0 {
+		return nil
+	}
+	delay := sinkRetryDelay
+	for attempt := 0; ; attempt++ {
+		err := s.deliver(s.ctx, evs)
+		if err == nil {
+			return nil
+		}
+		if attempt >= s.opts.MaxRetries || s.ctx.Err() != nil {
+			logrus.WithError(err).WithField("sink", s.opts.Name).Warn("failed to deliver events to sink")
+			return err
+		}
+		logrus.WithError(err).WithField("sink", s.opts.Name).Debugf("failed to deliver events to sink, retrying in %s", delay)
+		select {
+		case <-time.After(delay):
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		}
+		if delay *= 2; delay > sinkMaxRetryDelay {
+			delay = sinkMaxRetryDelay
+		}
+	}
+}
+
+func (s *Sink) spoolEvents(evs []eventtypes.Message) {
+	if len(evs) == 0 {
+		return
+	}
+	if err := s.spool.push(evs); err != nil {
+		logrus.WithError(err).WithField("sink", s.opts.Name).Errorf("failed to spool events, dropping %d events", len(evs))
+	}
+}
+
+func postEvents(ctx context.Context, client *http.Client, address string, evs []eventtypes.Message) error {
+	b, err := json.Marshal(evs)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, address, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code from %s: %s", address, resp.Status)
+	}
+	return nil
+}
+
+func writeEvents(ctx context.Context, network, address string, evs []eventtypes.Message) error {
+	ctx, cancel := context.WithTimeout(ctx, sinkTimeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetWriteDeadline(deadline)
+	}
+
+	if network == SinkTypeUnixgram {
+		for _, ev := range evs {
+			b, err := json.Marshal(ev)
+			if err != nil {
+				return err
+			}
+			if _, err := conn.Write(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, ev := range evs {
+		if err := enc.Encode(ev); err != nil {
+			return err
+		}
+	}
+	_, err = conn.Write(buf.Bytes())
+	return err
+}
+
+// sinkSpool stores batches of events on disk, one file per batch. When the
+// spool is larger than its maximum size, the oldest batches are dropped.
+type sinkSpool struct {
+	mu      sync.Mutex
+	root    string
+	maxSize int64
+	seq     uint64
+	files   []spoolFile // oldest first
+}
+
+type spoolFile struct {
+	name string
+	size int64
+}
+
+const spoolExt = ".json"
+
+func newSinkSpool(root string, maxSize int64) (*sinkSpool, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create event sink spool directory")
+	}
+	fis, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read event sink spool directory")
+	}
+	sp := &sinkSpool{root: root, maxSize: maxSize}
+	for _, fi := range fis {
+		if fi.IsDir() || !strings.HasSuffix(fi.Name(), spoolExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(fi.Name(), spoolExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		if seq > sp.seq {
+			sp.seq = seq
+		}
+		sp.files = append(sp.files, spoolFile{name: fi.Name(), size: fi.Size()})
+	}
+	sort.Slice(sp.files, func(i, j int) bool { return spoolSeq(sp.files[i].name) < spoolSeq(sp.files[j].name) })
+	return sp, nil
+}
+
+func spoolSeq(name string) uint64 {
+	seq, _ := strconv.ParseUint(strings.TrimSuffix(name, spoolExt), 10, 64)
+	return seq
+}
+
+// push adds a batch of events to the spool.
+func (sp *sinkSpool) push(evs []eventtypes.Message) error {
+	b, err := json.Marshal(evs)
+	if err != nil {
+		return err
+	}
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	sp.seq++
+	name := strconv.FormatUint(sp.seq, 10) + spoolExt
+	if err := ioutil.WriteFile(filepath.Join(sp.root, name), b, 0600); err != nil {
+		return err
+	}
+	sp.files = append(sp.files, spoolFile{name: name, size: int64(len(b))})
+
+	var size int64
+	for _, f := range sp.files {
+		size += f.size
+	}
+	var dropped int
+	for size > sp.maxSize && len(sp.files) > 0 {
+		f := sp.files[0]
+		if err := os.Remove(filepath.Join(sp.root, f.name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		size -= f.size
+		sp.files = sp.files[1:]
+		dropped++
+	}
+	if dropped > 0 {
+		logrus.WithField("spool", sp.root).Warnf("event sink spool is full, dropped %d oldest batches of events", dropped)
+	}
+	return nil
+}
+
+// oldest returns the name and events of the oldest batch in the spool, or an
+// empty name if the spool is empty. Unreadable batches are dropped.
+func (sp *sinkSpool) oldest() (string, []eventtypes.Message, error) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	if len(sp.files) == 0 {
+		return "", nil, nil
+	}
+	f := sp.files[0]
+	b, err := ioutil.ReadFile(filepath.Join(sp.root, f.name))
+	if err == nil {
+		var evs []eventtypes.Message
+		if err = json.Unmarshal(b, &evs); err == nil {
+			return f.name, evs, nil
+		}
+	}
+	os.Remove(filepath.Join(sp.root, f.name))
+	sp.files = sp.files[1:]
+	return "", nil, err
+}
+
+// remove removes a batch from the spool once it has been delivered.
+func (sp *sinkSpool) remove(name string) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	for i, f := range sp.files {
+		if f.name == name {
+			sp.files = append(sp.files[:i], sp.files[i+1:]...)
+			break
+		}
+	}
+	if err := os.Remove(filepath.Join(sp.root, name)); err != nil && !os.IsNotExist(err) {
+		logrus.WithError(err).WithField("spool", sp.root).Warn("failed to remove delivered events from spool")
+	}
+}
@@ -0,0 +1,254 @@
+package events // import "github.com/docker/docker/daemon/events"
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+type webhookReceiver struct {
+	mu     sync.Mutex
+	events []events.Message
+	posts  int
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var evs []events.Message
+	if err := json.NewDecoder(req.Body).Decode(&evs); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.posts++
+	r.events = append(r.events, evs...)
+}
+
+func (r *webhookReceiver) received() ([]events.Message, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]events.Message(nil), r.events...), r.posts
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timeout waiting for condition")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSinkWebhookBatchAndFilter(t *testing.T) {
+	root, err := ioutil.TempDir("", "events-sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	r := &webhookReceiver{}
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	s, err := NewSink(SinkOptions{
+		Name:         "test",
+		Type:         SinkTypeWebhook,
+		Address:      srv.URL,
+		Filter:       NewFilter(filters.NewArgs(filters.Arg("type", events.ContainerEventType))),
+		BatchSize:    3,
+		BatchTimeout: time.Hour,
+		SpoolDir:     root,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	for _, typ := range []string{events.ContainerEventType, events.ImageEventType, events.ContainerEventType, events.ContainerEventType} {
+		s.Publish(events.Message{Type: typ, Action: "create"})
+	}
+	waitFor(t, func() bool {
+		_, posts := r.received()
+		return posts == 1
+	})
+	evs, _ := r.received()
+	if len(evs) != 3 {
+		t.Fatalf("expected a batch of 3 events, got %d", len(evs))
+	}
+	for _, ev := range evs {
+		if ev.Type != events.ContainerEventType {
+			t.Fatalf("expected only container events, got %q", ev.Type)
+		}
+	}
+}
+
+func TestSinkSpool(t *testing.T) {
+	defer func(d time.Duration) { sinkRetryDelay = d }(sinkRetryDelay)
+	sinkRetryDelay = time.Millisecond
+
+	root, err := ioutil.TempDir("", "events-sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	// the endpoint is unreachable at first
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	opts := SinkOptions{
+		Name:         "test",
+		Type:         SinkTypeWebhook,
+		Address:      unreachable.URL,
+		BatchSize:    1,
+		BatchTimeout: 10 * time.Millisecond,
+		MaxRetries:   1,
+		SpoolDir:     root,
+	}
+	s, err := NewSink(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Publish(events.Message{Action: "1"})
+	s.Publish(events.Message{Action: "2"})
+	waitFor(t, func() bool {
+		fis, _ := ioutil.ReadDir(root)
+		return len(fis) == 2
+	})
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the spool is delivered once the endpoint is reachable, after the sink
+	// is created again
+	r := &webhookReceiver{}
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+	opts.Address = srv.URL
+	s, err = NewSink(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	s.Publish(events.Message{Action: "3"})
+	waitFor(t, func() bool {
+		evs, _ := r.received()
+		return len(evs) == 3
+	})
+	evs, _ := r.received()
+	for i, action := range []string{"1", "2", "3"} {
+		if evs[i].Action != action {
+			t.Fatalf("expected event %d to be %q, got %q", i, action, evs[i].Action)
+		}
+	}
+	waitFor(t, func() bool {
+		fis, _ := ioutil.ReadDir(root)
+		return len(fis) == 0
+	})
+}
+
+func TestSinkSpoolMaxSize(t *testing.T) {
+	root, err := ioutil.TempDir("", "events-sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	sp, err := newSinkSpool(root, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if err := sp.push([]events.Message{{Action: "create", Actor: events.Actor{ID: "0123456789abcdef"}}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var size int64
+	fis, err := ioutil.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fi := range fis {
+		size += fi.Size()
+	}
+	if size > 1024 {
+		t.Fatalf("expected spool to be at most 1024 bytes, got %d", size)
+	}
+	name, _, err := sp.oldest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name == "1"+spoolExt {
+		t.Fatal("expected the oldest events to be dropped")
+	}
+}
+
+func TestSinkUnixgram(t *testing.T) {
+	root, err := ioutil.TempDir("", "events-sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	addr := filepath.Join(root, "events.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	s, err := NewSink(SinkOptions{
+		Name:         "test",
+		Type:         SinkTypeUnixgram,
+		Address:      addr,
+		BatchTimeout: 10 * time.Millisecond,
+		SpoolDir:     filepath.Join(root, "spool"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	s.Publish(events.Message{Action: "create"})
+	s.Publish(events.Message{Action: "start"})
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 4096)
+	for _, action := range []string{"create", "start"} {
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ev events.Message
+		if err := json.Unmarshal(buf[:n], &ev); err != nil {
+			t.Fatal(err)
+		}
+		if ev.Action != action {
+			t.Fatalf("expected %q, got %q", action, ev.Action)
+		}
+	}
+}
+
+func TestNewSinkInvalid(t *testing.T) {
+	for _, opts := range []SinkOptions{
+		{Name: "test", Type: "smtp", Address: "/run/events.sock"},
+		{Name: "test", Type: SinkTypeWebhook, Address: "/run/events.sock"},
+		{Name: "test", Type: SinkTypeUnix, Address: "events.sock"},
+		{Type: SinkTypeUnix, Address: "/run/events.sock"},
+	} {
+		if _, err := NewSink(opts); err == nil {
+			t.Fatalf("expected an error for %+v", opts)
+		}
+	}
+}
@@ -3,16 +3,25 @@ package daemon // import "github.com/docker/docker/daemon"
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/strslice"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/exec"
+	"github.com/docker/docker/errdefs"
 	"github.com/sirupsen/logrus"
 )
 
@@ -38,14 +47,41 @@ const (
 
 	// Maximum number of entries to record
 	maxLogEntries = 5
+
+	// Status codes an HTTP probe considers healthy if no range is configured.
+	defaultHTTPProbeStatus = "200-399"
 )
 
 const (
 	// Exit status codes that can be returned by the probe command.
 
-	exitStatusHealthy = 0 // Container is healthy
+	exitStatusHealthy   = 0 // Container is healthy
+	exitStatusUnhealthy = 1 // Container is unhealthy
+)
+
+// checkKind identifies which of the container's checks a monitor runs.
+type checkKind int
+
+const (
+	healthCheck    checkKind = iota // Config.Healthcheck, reported as the health status
+	readinessCheck                  // Config.Readinesscheck, reported as the readiness status
 )
 
+// config returns the container's configuration for the check.
+func (k checkKind) config(c *container.Container) *containertypes.HealthConfig {
+	if k == readinessCheck {
+		return c.Config.Readinesscheck
+	}
+	return c.Config.Healthcheck
+}
+
+func (k checkKind) String() string {
+	if k == readinessCheck {
+		return "Readiness check"
+	}
+	return "Health check"
+}
+
 // probe implementations know how to run a particular type of probe.
 type probe interface {
 	// Perform one run of the check. Returns the exit code and an optional
@@ -57,12 +93,14 @@ type probe interface {
 type cmdProbe struct {
 	// Run the command with the system's default shell instead of execing it directly.
 	shell bool
+	// The command to run, without the leading "CMD" or "CMD-SHELL".
+	cmd []string
 }
 
 // exec the healthcheck command in the container.
 // Returns the exit code and probe output (if any)
 func (p *cmdProbe) run(ctx context.Context, d *Daemon, cntr *container.Container) (*types.HealthcheckResult, error) {
-	cmdSlice := strslice.StrSlice(cntr.Config.Healthcheck.Test)[1:]
+	cmdSlice := strslice.StrSlice(p.cmd)
 	if p.shell {
 		cmdSlice = append(getShell(cntr), cmdSlice...)
 	}
@@ -113,8 +151,140 @@ func (p *cmdProbe) run(ctx context.Context, d *Daemon, cntr *container.Container
 	}, nil
 }
 
+// httpProbe implements the "HTTP" probe type. The request is made by the daemon
+// from the container's network namespace, so the container does not need any
+// tooling to be checked.
+type httpProbe struct {
+	url       string
+	minStatus int
+	maxStatus int
+}
+
+// newHTTPProbe parses the arguments of an "HTTP" probe: a URL and an optional
+// range of expected status codes.
+func newHTTPProbe(args []string) (*httpProbe, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("HTTP health check requires a URL and an optional status range")
+	}
+	u, err := url.Parse(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP health check URL: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid HTTP health check URL %q: scheme must be http or https", args[0])
+	}
+	statusRange := defaultHTTPProbeStatus
+	if len(args) == 2 {
+		statusRange = args[1]
+	}
+	minStatus, maxStatus, err := parseStatusRange(statusRange)
+	if err != nil {
+		return nil, err
+	}
+	return &httpProbe{url: args[0], minStatus: minStatus, maxStatus: maxStatus}, nil
+}
+
+// parseStatusRange parses a status code ("200") or a range of status codes
+// ("200-299").
+func parseStatusRange(s string) (int, int, error) {
+	minStr, maxStr := s, s
+	if i := strings.Index(s, "-"); i >= 0 {
+		minStr, maxStr = s[:i], s[i+1:]
+	}
+	minStatus, err := strconv.Atoi(minStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid HTTP health check status range %q", s)
+	}
+	maxStatus, err := strconv.Atoi(maxStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid HTTP health check status range %q", s)
+	}
+	if minStatus < 100 || maxStatus > 599 || minStatus > maxStatus {
+		return 0, 0, fmt.Errorf("invalid HTTP health check status range %q", s)
+	}
+	return minStatus, maxStatus, nil
+}
+
+// GET the URL from the container's network namespace.
+// The container is healthy if the response has one of the expected status codes.
+func (p *httpProbe) run(ctx context.Context, d *Daemon, cntr *container.Container) (*types.HealthcheckResult, error) {
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				return dialContainer(ctx, cntr, network, address)
+			},
+			DisableKeepAlives: true,
+			// Probes check that the service responds, not who it is.
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // nolint: gosec
+		},
+	}
+	req, err := http.NewRequest(http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return &types.HealthcheckResult{
+			End:      time.Now(),
+			ExitCode: exitStatusUnhealthy,
+			Output:   err.Error(),
+		}, nil
+	}
+	defer resp.Body.Close()
+
+	output := &limitedBuffer{}
+	fmt.Fprintf(output, "GET %s: %s\n", p.url, resp.Status)
+	io.Copy(output, io.LimitReader(resp.Body, maxOutputLen))
+
+	exitCode := exitStatusHealthy
+	if resp.StatusCode < p.minStatus || resp.StatusCode > p.maxStatus {
+		exitCode = exitStatusUnhealthy
+	}
+	return &types.HealthcheckResult{
+		End:      time.Now(),
+		ExitCode: exitCode,
+		Output:   output.String(),
+	}, nil
+}
+
+// tcpProbe implements the "TCP" probe type. The connection is made by the
+// daemon from the container's network namespace.
+type tcpProbe struct {
+	address string
+}
+
+// newTCPProbe parses the arguments of a "TCP" probe: a "host:port" address.
+func newTCPProbe(args []string) (*tcpProbe, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("TCP health check requires an address")
+	}
+	if _, _, err := net.SplitHostPort(args[0]); err != nil {
+		return nil, fmt.Errorf("invalid TCP health check address: %v", err)
+	}
+	return &tcpProbe{address: args[0]}, nil
+}
+
+// Connect to the address from the container's network namespace.
+// The container is healthy if the connection succeeds.
+func (p *tcpProbe) run(ctx context.Context, d *Daemon, cntr *container.Container) (*types.HealthcheckResult, error) {
+	conn, err := dialContainer(ctx, cntr, "tcp", p.address)
+	if err != nil {
+		return &types.HealthcheckResult{
+			End:      time.Now(),
+			ExitCode: exitStatusUnhealthy,
+			Output:   err.Error(),
+		}, nil
+	}
+	conn.Close()
+	return &types.HealthcheckResult{
+		End:      time.Now(),
+		ExitCode: exitStatusHealthy,
+		Output:   "connected to " + p.address,
+	}, nil
+}
+
 // Update the container's Status.Health struct based on the latest probe's result.
-func handleProbeResult(d *Daemon, c *container.Container, result *types.HealthcheckResult, done chan struct{}) {
+func handleProbeResult(d *Daemon, c *container.Container, kind checkKind, result *types.HealthcheckResult, done chan struct{}) {
 	c.Lock()
 	defer c.Unlock()
 
@@ -125,40 +295,29 @@ func handleProbeResult(d *Daemon, c *container.Container, result *types.Healthch
 	default:
 	}
 
-	retries := c.Config.Healthcheck.Retries
+	config := kind.config(c)
+	retries := config.Retries
 	if retries <= 0 {
 		retries = defaultProbeRetries
 	}
 
 	h := c.State.Health
+	if kind == readinessCheck {
+		handleReadinessResult(d, c, config, retries, result)
+		return
+	}
 	oldStatus := h.Status()
 
-	if len(h.Log) >= maxLogEntries {
-		h.Log = append(h.Log[len(h.Log)+1-maxLogEntries:], result)
-	} else {
-		h.Log = append(h.Log, result)
-	}
+	h.Log = appendProbeResult(h.Log, result)
 
 	if result.ExitCode == exitStatusHealthy {
 		h.FailingStreak = 0
 		h.SetStatus(types.Healthy)
 	} else { // Failure (including invalid exit code)
-		shouldIncrementStreak := true
-
 		// If the container is starting (i.e. we never had a successful health check)
 		// then we check if we are within the start period of the container in which
 		// case we do not increment the failure streak.
-		if h.Status() == types.Starting {
-			startPeriod := timeoutWithDefault(c.Config.Healthcheck.StartPeriod, defaultStartPeriod)
-			timeSinceStart := result.Start.Sub(c.State.StartedAt)
-
-			// If still within the start period, then don't increment failing streak.
-			if timeSinceStart < startPeriod {
-				shouldIncrementStreak = false
-			}
-		}
-
-		if shouldIncrementStreak {
+		if !inStartPeriod(c, config, oldStatus, result) {
 			h.FailingStreak++
 
 			if h.FailingStreak >= retries {
@@ -179,13 +338,101 @@ func handleProbeResult(d *Daemon, c *container.Container, result *types.Healthch
 	if oldStatus != current {
 		d.LogContainerEvent(c, "health_status: "+current)
 	}
+
+	if current == types.Unhealthy && c.HostConfig != nil && c.HostConfig.RestartPolicy.IsOnUnhealthy() {
+		if since := h.UnhealthySince(); !since.IsZero() {
+			if unhealthyFor := time.Since(since); unhealthyFor >= c.HostConfig.RestartPolicy.UnhealthyTimeout {
+				go d.restartUnhealthy(c, unhealthyFor)
+			}
+		}
+	}
 }
 
-// Run the container's monitoring thread until notified via "stop".
-// There is never more than one monitor thread running per container at a time.
-func monitor(d *Daemon, c *container.Container, stop chan struct{}, probe probe) {
-	probeTimeout := timeoutWithDefault(c.Config.Healthcheck.Timeout, defaultProbeTimeout)
-	probeInterval := timeoutWithDefault(c.Config.Healthcheck.Interval, defaultProbeInterval)
+// Update the container's readiness state based on the latest readiness probe's
+// result. Called with c locked.
+func handleReadinessResult(d *Daemon, c *container.Container, config *containertypes.HealthConfig, retries int, result *types.HealthcheckResult) {
+	h := c.State.Health
+	oldStatus := h.ReadinessStatus()
+
+	h.Readiness.Log = appendProbeResult(h.Readiness.Log, result)
+
+	if result.ExitCode == exitStatusHealthy {
+		h.Readiness.FailingStreak = 0
+		h.SetReadinessStatus(types.Ready)
+	} else if !inStartPeriod(c, config, oldStatus, result) {
+		h.Readiness.FailingStreak++
+
+		if h.Readiness.FailingStreak >= retries {
+			h.SetReadinessStatus(types.NotReady)
+		}
+	}
+
+	// replicate Readiness status changes
+	if err := c.CheckpointTo(d.containersReplica); err != nil {
+		logrus.Errorf("Error replicating readiness state for container %s: %v", c.ID, err)
+	}
+
+	current := h.ReadinessStatus()
+	if oldStatus != current {
+		d.LogContainerEvent(c, "readiness_status: "+current)
+	}
+}
+
+// appendProbeResult appends result to log, keeping at most maxLogEntries entries.
+func appendProbeResult(log []*types.HealthcheckResult, result *types.HealthcheckResult) []*types.HealthcheckResult {
+	if len(log) >= maxLogEntries {
+		return append(log[len(log)+1-maxLogEntries:], result)
+	}
+	return append(log, result)
+}
+
+// inStartPeriod returns true if a check that never succeeded yet (i.e. its
+// status is still starting) failed within the start period of the container.
+// Such failures do not count towards the failing streak.
+func inStartPeriod(c *container.Container, config *containertypes.HealthConfig, status string, result *types.HealthcheckResult) bool {
+	if status != types.Starting {
+		return false
+	}
+	startPeriod := timeoutWithDefault(config.StartPeriod, defaultStartPeriod)
+	return result.Start.Sub(c.State.StartedAt) < startPeriod
+}
+
+// restartUnhealthy stops a container that stayed unhealthy for longer than
+// its restart policy allows. Restarting it is left to the restart manager,
+// so that the usual backoff applies.
+func (d *Daemon) restartUnhealthy(c *container.Container, unhealthyFor time.Duration) {
+	c.Lock()
+	if !c.Running || c.Paused || c.Restarting || !c.RestartManager().RestartUnhealthy() {
+		c.Unlock()
+		return
+	}
+	d.LogContainerEventWithAttributes(c, "restart-unhealthy", map[string]string{
+		"unhealthyFor": unhealthyFor.String(),
+	})
+	c.Unlock()
+
+	stopSignal := c.StopSignal()
+	if err := d.kill(c, stopSignal); err != nil && !errdefs.IsNotFound(err) {
+		logrus.WithError(err).WithField("container", c.ID).Warnf("failed to send signal %d to unhealthy container", stopSignal)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.StopTimeout())*time.Second)
+	defer cancel()
+	if status := <-c.Wait(ctx, container.WaitConditionNotRunning); status.Err() != nil {
+		logrus.Infof("Unhealthy container %s failed to exit within %d seconds of signal %d - using the force", c.ID, c.StopTimeout(), stopSignal)
+		if err := d.kill(c, int(syscall.SIGKILL)); err != nil && !errdefs.IsNotFound(err) {
+			logrus.WithError(err).WithField("container", c.ID).Warn("failed to kill unhealthy container")
+		}
+	}
+}
+
+// Run the container's monitoring thread for a check until notified via "stop".
+// There is never more than one monitor thread running per check and container
+// at a time.
+func monitor(d *Daemon, c *container.Container, kind checkKind, stop chan struct{}, probe probe) {
+	config := kind.config(c)
+	probeTimeout := timeoutWithDefault(config.Timeout, defaultProbeTimeout)
+	probeInterval := timeoutWithDefault(config.Interval, defaultProbeInterval)
 
 	intervalTimer := time.NewTimer(probeInterval)
 	defer intervalTimer.Stop()
@@ -195,10 +442,10 @@ func monitor(d *Daemon, c *container.Container, stop chan struct{}, probe probe)
 
 		select {
 		case <-stop:
-			logrus.Debugf("Stop healthcheck monitoring for container %s (received while idle)", c.ID)
+			logrus.Debugf("%s monitoring stopped for container %s (received while idle)", kind, c.ID)
 			return
 		case <-intervalTimer.C:
-			logrus.Debugf("Running health check for container %s ...", c.ID)
+			logrus.Debugf("Running %s for container %s ...", strings.ToLower(kind.String()), c.ID)
 			startTime := time.Now()
 			ctx, cancelProbe := context.WithTimeout(context.Background(), probeTimeout)
 			results := make(chan *types.HealthcheckResult, 1)
@@ -207,7 +454,7 @@ func monitor(d *Daemon, c *container.Container, stop chan struct{}, probe probe)
 				result, err := probe.run(ctx, d, c)
 				if err != nil {
 					healthChecksFailedCounter.Inc()
-					logrus.Warnf("Health check for container %s error: %v", c.ID, err)
+					logrus.Warnf("%s for container %s error: %v", kind, c.ID, err)
 					results <- &types.HealthcheckResult{
 						ExitCode: -1,
 						Output:   err.Error(),
@@ -216,28 +463,28 @@ func monitor(d *Daemon, c *container.Container, stop chan struct{}, probe probe)
 					}
 				} else {
 					result.Start = startTime
-					logrus.Debugf("Health check for container %s done (exitCode=%d)", c.ID, result.ExitCode)
+					logrus.Debugf("%s for container %s done (exitCode=%d)", kind, c.ID, result.ExitCode)
 					results <- result
 				}
 				close(results)
 			}()
 			select {
 			case <-stop:
-				logrus.Debugf("Stop healthcheck monitoring for container %s (received while probing)", c.ID)
+				logrus.Debugf("%s monitoring stopped for container %s (received while probing)", kind, c.ID)
 				cancelProbe()
 				// Wait for probe to exit (it might take a while to respond to the TERM
 				// signal and we don't want dying probes to pile up).
 				<-results
 				return
 			case result := <-results:
-				handleProbeResult(d, c, result, stop)
+				handleProbeResult(d, c, kind, result, stop)
 				// Stop timeout
 				cancelProbe()
 			case <-ctx.Done():
-				logrus.Debugf("Health check for container %s taking too long", c.ID)
-				handleProbeResult(d, c, &types.HealthcheckResult{
+				logrus.Debugf("%s for container %s taking too long", kind, c.ID)
+				handleProbeResult(d, c, kind, &types.HealthcheckResult{
 					ExitCode: -1,
-					Output:   fmt.Sprintf("Health check exceeded timeout (%v)", probeTimeout),
+					Output:   fmt.Sprintf("%s exceeded timeout (%v)", kind, probeTimeout),
 					Start:    startTime,
 					End:      time.Now(),
 				}, stop)
@@ -250,27 +497,41 @@ func monitor(d *Daemon, c *container.Container, stop chan struct{}, probe probe)
 	}
 }
 
-// Get a suitable probe implementation for the container's healthcheck configuration.
-// Nil will be returned if no healthcheck was configured or NONE was set.
-func getProbe(c *container.Container) probe {
-	config := c.Config.Healthcheck
+// Get a suitable probe implementation for the container's check configuration.
+// Nil will be returned if no check was configured or NONE was set.
+func getProbe(c *container.Container, kind checkKind) probe {
+	config := kind.config(c)
 	if config == nil || len(config.Test) == 0 {
 		return nil
 	}
 	switch config.Test[0] {
 	case "CMD":
-		return &cmdProbe{shell: false}
+		return &cmdProbe{shell: false, cmd: config.Test[1:]}
 	case "CMD-SHELL":
-		return &cmdProbe{shell: true}
+		return &cmdProbe{shell: true, cmd: config.Test[1:]}
+	case "HTTP":
+		p, err := newHTTPProbe(config.Test[1:])
+		if err != nil {
+			logrus.Warnf("Invalid %s in container %s: %v", strings.ToLower(kind.String()), c.ID, err)
+			return nil
+		}
+		return p
+	case "TCP":
+		p, err := newTCPProbe(config.Test[1:])
+		if err != nil {
+			logrus.Warnf("Invalid %s in container %s: %v", strings.ToLower(kind.String()), c.ID, err)
+			return nil
+		}
+		return p
 	case "NONE":
 		return nil
 	default:
-		logrus.Warnf("Unknown healthcheck type '%s' (expected 'CMD') in container %s", config.Test[0], c.ID)
+		logrus.Warnf("Unknown %s type '%s' (expected 'CMD', 'HTTP' or 'TCP') in container %s", strings.ToLower(kind.String()), config.Test[0], c.ID)
 		return nil
 	}
 }
 
-// Ensure the health-check monitor is running or not, depending on the current
+// Ensure the health-check monitors are running or not, depending on the current
 // state of the container.
 // Called from monitor.go, with c locked.
 func (d *Daemon) updateHealthMonitor(c *container.Container) {
@@ -279,11 +540,16 @@ func (d *Daemon) updateHealthMonitor(c *container.Container) {
 		return // No healthcheck configured
 	}
 
-	probe := getProbe(c)
-	wantRunning := c.Running && !c.Paused && probe != nil
+	healthProbe, readinessProbe := getProbe(c, healthCheck), getProbe(c, readinessCheck)
+	wantRunning := c.Running && !c.Paused && (healthProbe != nil || readinessProbe != nil)
 	if wantRunning {
 		if stop := h.OpenMonitorChannel(); stop != nil {
-			go monitor(d, c, stop, probe)
+			if healthProbe != nil {
+				go monitor(d, c, healthCheck, stop, healthProbe)
+			}
+			if readinessProbe != nil {
+				go monitor(d, c, readinessCheck, stop, readinessProbe)
+			}
 		}
 	} else {
 		h.CloseMonitorChannel()
@@ -295,21 +561,31 @@ func (d *Daemon) updateHealthMonitor(c *container.Container) {
 // two instances at once.
 // Called with c locked.
 func (d *Daemon) initHealthMonitor(c *container.Container) {
+	hasHealthcheck, hasReadinesscheck := getProbe(c, healthCheck) != nil, getProbe(c, readinessCheck) != nil
 	// If no healthcheck is setup then don't init the monitor
-	if getProbe(c) == nil {
+	if !hasHealthcheck && !hasReadinesscheck {
 		return
 	}
 
 	// This is needed in case we're auto-restarting
 	d.stopHealthchecks(c)
 
-	if h := c.State.Health; h != nil {
+	h := c.State.Health
+	if h == nil {
+		h = &container.Health{}
+		c.State.Health = h
+	}
+	if hasHealthcheck {
 		h.SetStatus(types.Starting)
-		h.FailingStreak = 0
 	} else {
-		h := &container.Health{}
-		h.SetStatus(types.Starting)
-		c.State.Health = h
+		h.SetStatus(types.NoHealthcheck)
+	}
+	h.FailingStreak = 0
+	if hasReadinesscheck {
+		h.SetReadinessStatus(types.Starting)
+		h.Readiness.FailingStreak = 0
+	} else {
+		h.Readiness = nil
 	}
 
 	d.updateHealthMonitor(c)
@@ -0,0 +1,56 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"net"
+	"runtime"
+
+	"github.com/docker/docker/container"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netns"
+)
+
+// dialContainer connects to address from the network namespace of the
+// container, so that probes reach services that are only listening inside
+// of the container.
+func dialContainer(ctx context.Context, c *container.Container, network, address string) (net.Conn, error) {
+	pid := c.GetPID()
+	if pid == 0 {
+		return nil, errNotRunning(c.ID)
+	}
+	containerNS, err := netns.GetFromPid(pid)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get network namespace of container %s", c.ID)
+	}
+	defer containerNS.Close()
+
+	// The namespace is a property of the OS thread; sockets created while
+	// switched keep belonging to the container's namespace afterwards.
+	runtime.LockOSThread()
+	hostNS, err := netns.Get()
+	if err != nil {
+		runtime.UnlockOSThread()
+		return nil, errors.Wrap(err, "failed to get current network namespace")
+	}
+	defer hostNS.Close()
+
+	if err := netns.Set(containerNS); err != nil {
+		runtime.UnlockOSThread()
+		return nil, errors.Wrapf(err, "failed to enter network namespace of container %s", c.ID)
+	}
+	defer func() {
+		if err := netns.Set(hostNS); err != nil {
+			// Keep the thread locked, so that it is terminated together with
+			// this goroutine instead of being reused in the wrong namespace.
+			logrus.WithError(err).Error("failed to restore network namespace after health check")
+			return
+		}
+		runtime.UnlockOSThread()
+	}()
+
+	// Dial serially, as parallel dials would run on other threads, outside
+	// of the container's namespace.
+	dialer := net.Dialer{FallbackDelay: -1}
+	return dialer.DialContext(ctx, network, address)
+}
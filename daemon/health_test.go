@@ -86,7 +86,7 @@ func TestHealthStates(t *testing.T) {
 	reset(c)
 
 	handleResult := func(startTime time.Time, exitCode int) {
-		handleProbeResult(daemon, c, &types.HealthcheckResult{
+		handleProbeResult(daemon, c, healthCheck, &types.HealthcheckResult{
 			Start:    startTime,
 			End:      startTime,
 			ExitCode: exitCode,
@@ -152,3 +152,127 @@ func TestHealthStates(t *testing.T) {
 		t.Errorf("Expecting FailingStreak=0, but got %d\n", c.State.Health.FailingStreak)
 	}
 }
+
+func TestReadinessStates(t *testing.T) {
+	e := events.New()
+	_, l, _ := e.Subscribe()
+	defer e.Evict(l)
+
+	expect := func(expected string) {
+		select {
+		case event := <-l:
+			ev := event.(eventtypes.Message)
+			if ev.Status != expected {
+				t.Errorf("Expecting event %#v, but got %#v\n", expected, ev.Status)
+			}
+		case <-time.After(1 * time.Second):
+			t.Errorf("Expecting event %#v, but got nothing\n", expected)
+		}
+	}
+
+	c := &container.Container{
+		ID:   "container_id",
+		Name: "container_name",
+		Config: &containertypes.Config{
+			Image: "image_name",
+			Readinesscheck: &containertypes.HealthConfig{
+				Test:        []string{"CMD", "true"},
+				Retries:     2,
+				StartPeriod: 30 * time.Second,
+			},
+		},
+		State: &container.State{},
+	}
+
+	store, err := container.NewViewDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	daemon := &Daemon{
+		EventsService:     e,
+		containersReplica: store,
+	}
+
+	daemon.initHealthMonitor(c)
+	if status := c.State.Health.Status(); status != types.NoHealthcheck {
+		t.Errorf("Expecting none, but got %#v\n", status)
+	}
+	if status := c.State.Health.ReadinessStatus(); status != types.Starting {
+		t.Errorf("Expecting starting, but got %#v\n", status)
+	}
+
+	handleResult := func(startTime time.Time, exitCode int) {
+		handleProbeResult(daemon, c, readinessCheck, &types.HealthcheckResult{
+			Start:    startTime,
+			End:      startTime,
+			ExitCode: exitCode,
+		}, nil)
+	}
+
+	// failures within the start period don't count
+	handleResult(c.State.StartedAt.Add(10*time.Second), 1)
+	handleResult(c.State.StartedAt.Add(20*time.Second), 1)
+	if status := c.State.Health.ReadinessStatus(); status != types.Starting {
+		t.Errorf("Expecting starting, but got %#v\n", status)
+	}
+
+	handleResult(c.State.StartedAt.Add(40*time.Second), 0)
+	expect("readiness_status: ready")
+
+	handleResult(c.State.StartedAt.Add(50*time.Second), 1)
+	if c.State.Health.Readiness.FailingStreak != 1 {
+		t.Errorf("Expecting FailingStreak=1, but got %d\n", c.State.Health.Readiness.FailingStreak)
+	}
+	handleResult(c.State.StartedAt.Add(60*time.Second), 1)
+	expect("readiness_status: not-ready")
+
+	if status := c.State.Health.Status(); status != types.NoHealthcheck {
+		t.Errorf("Expecting the health status to be unaffected, but got %#v\n", status)
+	}
+	if len(c.State.Health.Readiness.Log) != 5 || len(c.State.Health.Log) != 0 {
+		t.Errorf("Expecting results to be logged in the readiness log only")
+	}
+}
+
+func TestHTTPProbeArgs(t *testing.T) {
+	p, err := newHTTPProbe([]string{"http://localhost:8080/healthz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.minStatus != 200 || p.maxStatus != 399 {
+		t.Errorf("Expecting the default status range 200-399, but got %d-%d", p.minStatus, p.maxStatus)
+	}
+
+	p, err = newHTTPProbe([]string{"https://localhost/ready", "204"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.minStatus != 204 || p.maxStatus != 204 {
+		t.Errorf("Expecting the status range 204-204, but got %d-%d", p.minStatus, p.maxStatus)
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"localhost:8080"},
+		{"ftp://localhost/"},
+		{"http://localhost/", "299-200"},
+		{"http://localhost/", "2xx"},
+		{"http://localhost/", "200-299", "extra"},
+	} {
+		if _, err := newHTTPProbe(args); err == nil {
+			t.Errorf("Expecting an error for %q", args)
+		}
+	}
+}
+
+func TestTCPProbeArgs(t *testing.T) {
+	if _, err := newTCPProbe([]string{"localhost:5432"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{}, {"localhost"}, {"localhost:5432", "extra"}} {
+		if _, err := newTCPProbe(args); err == nil {
+			t.Errorf("Expecting an error for %q", args)
+		}
+	}
+}
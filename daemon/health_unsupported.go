@@ -0,0 +1,16 @@
+// +build !linux
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"errors"
+	"net"
+	"runtime"
+
+	"github.com/docker/docker/container"
+)
+
+func dialContainer(ctx context.Context, c *container.Container, network, address string) (net.Conn, error) {
+	return nil, errors.New("HTTP and TCP health checks are not supported on " + runtime.GOOS)
+}
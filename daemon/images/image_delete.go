@@ -22,8 +22,9 @@ const (
 	conflictRunningContainer
 	conflictActiveReference
 	conflictStoppedContainer
+	conflictProtected
 	conflictHard = conflictDependentChild | conflictRunningContainer
-	conflictSoft = conflictActiveReference | conflictStoppedContainer
+	conflictSoft = conflictActiveReference | conflictStoppedContainer | conflictProtected
 )
 
 // ImageDelete deletes the image referenced by the given imageRef from this
@@ -52,6 +53,7 @@ const (
 // Soft Conflict:
 // 	- any stopped container using the image.
 // 	- any repository tag or digest references to the image.
+// 	- the image is protected.
 //
 // The image cannot be removed if there are any hard conflicts and can be
 // removed if there are soft conflicts only if force is true.
@@ -75,6 +77,11 @@ func (i *ImageService) ImageDelete(imageRef string, force, prune bool) ([]types.
 	imgID := img.ID()
 	repoRefs := i.referenceStore.References(imgID.Digest())
 
+	if !force && i.imageStore.IsProtected(imgID) {
+		err := errors.Errorf("conflict: unable to remove %q (must force) - image %s is protected", imageRef, stringid.TruncateID(imgID.String()))
+		return nil, errdefs.Conflict(err)
+	}
+
 	using := func(c *container.Container) bool {
 		return c.ImageID == imgID
 	}
@@ -389,6 +396,15 @@ func (i *ImageService) checkImageDeleteConflict(imgID image.ID, mask conflictTyp
 		}
 	}
 
+	if mask&conflictProtected != 0 && i.imageStore.IsProtected(imgID) {
+		return &imageDeleteConflict{
+			imgID: imgID,
+			// never delete protected parent images quietly
+			used:    true,
+			message: "image is protected",
+		}
+	}
+
 	if mask&conflictStoppedContainer != 0 {
 		// Check if any stopped containers reference this image.
 		stopped := func(c *container.Container) bool {
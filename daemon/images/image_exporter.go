@@ -1,24 +1,37 @@
 package images // import "github.com/docker/docker/daemon/images"
 
 import (
+	"fmt"
 	"io"
 
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/image"
 	"github.com/docker/docker/image/tarexport"
 )
 
 // ExportImage exports a list of images to the given output stream. The
 // exported images are archived into a tar when written to the output
 // stream. All images with the given tag and all versions containing
-// the same tag are exported. names is the set of tags to export, and
+// the same tag are exported. names is the set of tags to export, format is
+// the format of the archive, either "docker" (the default) or "oci", and
 // outStream is the writer which the images are written to.
-func (i *ImageService) ExportImage(names []string, outStream io.Writer) error {
-	imageExporter := tarexport.NewTarExporter(i.imageStore, i.layerStores, i.referenceStore, i)
+func (i *ImageService) ExportImage(names []string, format string, outStream io.Writer) error {
+	var imageExporter image.Exporter
+	switch format {
+	case "", tarexport.FormatDocker:
+		imageExporter = tarexport.NewTarExporter(i.imageStore, i.layerStores, i.referenceStore, i)
+	case tarexport.FormatOCI:
+		imageExporter = tarexport.NewOCIExporter(i.imageStore, i.layerStores, i.referenceStore, i)
+	default:
+		return errdefs.InvalidParameter(fmt.Errorf("invalid format %q: must be %q or %q", format, tarexport.FormatDocker, tarexport.FormatOCI))
+	}
 	return imageExporter.Save(names, outStream)
 }
 
 // LoadImage uploads a set of images into the repository. This is the
 // complement of ImageExport.  The input stream is an uncompressed tar
-// ball containing images and metadata.
+// ball containing images and metadata, in the docker format or in the OCI
+// image layout.
 func (i *ImageService) LoadImage(inTar io.ReadCloser, outStream io.Writer, quiet bool) error {
 	imageExporter := tarexport.NewTarExporter(i.imageStore, i.layerStores, i.referenceStore, i)
 	return imageExporter.Load(inTar, outStream, quiet)
@@ -0,0 +1,189 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/image"
+	"github.com/sirupsen/logrus"
+)
+
+// GCPolicy is the policy of the automatic garbage collection of the unused
+// images.
+type GCPolicy struct {
+	// Root is the directory whose disk usage is watched.
+	Root string
+	// HighWatermark is the usage of the disk, in percent, above which the
+	// unused images are deleted, down to LowWatermark.
+	HighWatermark int
+	LowWatermark  int
+	// Interval is the interval between two checks of the usage of the disk.
+	Interval time.Duration
+	// ExcludeLabels are the labels, "key" or "key=value", of the images
+	// which are never deleted.
+	ExcludeLabels []string
+}
+
+// diskUsage returns the used and total bytes of the file system of path.
+// It's a variable to be replaced in tests.
+var diskUsage = getDiskUsage
+
+// RunGC runs a ticker to delete the unused images, in least recently used
+// order, when the usage of the disk of policy.Root is above the high
+// watermark of the policy. Each deleted image is logged as a "gc" event.
+func (i *ImageService) RunGC(policy GCPolicy) {
+	for range time.Tick(policy.Interval) {
+		i.collectImages(policy)
+	}
+}
+
+// collectImages deletes the unused images until the usage of the disk is
+// below the low watermark of policy, if it's above the high watermark.
+func (i *ImageService) collectImages(policy GCPolicy) {
+	used, err := diskUsagePercent(policy.Root)
+	if err != nil {
+		logrus.WithError(err).Warn("image GC: failed to get the disk usage")
+		return
+	}
+	if used < float64(policy.HighWatermark) {
+		return
+	}
+	// don't run concurrently with a prune
+	if !atomic.CompareAndSwapInt32(&i.pruneRunning, 0, 1) {
+		return
+	}
+	defer atomic.StoreInt32(&i.pruneRunning, 0)
+
+	logrus.Infof("image GC: disk usage of %s is %.1f%%, deleting unused images", policy.Root, used)
+	deleted := 0
+	for used > float64(policy.LowWatermark) {
+		// deleting the images can make their parents collectable
+		candidates := i.gcCandidates(policy.ExcludeLabels)
+		if len(candidates) == 0 {
+			break
+		}
+		progress := false
+		for _, c := range candidates {
+			if !i.gcDelete(c) {
+				continue
+			}
+			progress = true
+			deleted++
+			if used, err = diskUsagePercent(policy.Root); err != nil {
+				logrus.WithError(err).Warn("image GC: failed to get the disk usage")
+				return
+			}
+			if used <= float64(policy.LowWatermark) {
+				break
+			}
+		}
+		if !progress {
+			break
+		}
+	}
+	logrus.Infof("image GC: deleted %d images, disk usage of %s is %.1f%%", deleted, policy.Root, used)
+}
+
+type gcCandidate struct {
+	id       image.ID
+	lastUsed time.Time
+}
+
+// gcCandidates returns the images without children which are not used by any
+// container nor protected nor excluded by their labels, from the least
+// recently used.
+func (i *ImageService) gcCandidates(excludeLabels []string) []gcCandidate {
+	inUse := make(map[image.ID]struct{})
+	for _, c := range i.containers.List() {
+		inUse[c.ImageID] = struct{}{}
+	}
+
+	var candidates []gcCandidate
+	for id, img := range i.imageStore.Heads() {
+		if _, ok := inUse[id]; ok || i.imageStore.IsProtected(id) {
+			continue
+		}
+		if img.Config != nil && hasExcludedLabel(img.Config.Labels, excludeLabels) {
+			continue
+		}
+		candidates = append(candidates, gcCandidate{id: id, lastUsed: i.lastUsed(id, img)})
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		return candidates[a].lastUsed.Before(candidates[b].lastUsed)
+	})
+	return candidates
+}
+
+// lastUsed returns the last time the image was used by a container, or
+// created or tagged. The images which were stored before their last use was
+// tracked fall back to their creation date.
+func (i *ImageService) lastUsed(id image.ID, img *image.Image) time.Time {
+	lastUsed, _ := i.imageStore.GetLastUsed(id)
+	if lastUpdated, _ := i.imageStore.GetLastUpdated(id); lastUpdated.After(lastUsed) {
+		lastUsed = lastUpdated
+	}
+	if lastUsed.IsZero() {
+		return img.Created
+	}
+	return lastUsed
+}
+
+// gcDelete deletes the image of c with all its references, and returns
+// whether it was deleted.
+func (i *ImageService) gcDelete(c gcCandidate) bool {
+	refs := i.referenceStore.References(c.id.Digest())
+	var name string
+	if len(refs) > 0 {
+		name = reference.FamiliarString(refs[0])
+		for _, ref := range refs {
+			if _, err := i.ImageDelete(ref.String(), false, true); imageDeleteFailed(ref.String(), err) {
+				return false
+			}
+		}
+	} else {
+		hex := c.id.Digest().Hex()
+		if _, err := i.ImageDelete(hex, false, true); imageDeleteFailed(hex, err) {
+			return false
+		}
+	}
+
+	i.LogImageEventWithAttributes(c.id.String(), name, "gc", map[string]string{
+		"lastUsed": c.lastUsed.Format(time.RFC3339Nano),
+	})
+	return true
+}
+
+// hasExcludedLabel returns whether labels contain any of the exclude labels,
+// given as "key" or "key=value".
+func hasExcludedLabel(labels map[string]string, exclude []string) bool {
+	for _, e := range exclude {
+		kv := strings.SplitN(e, "=", 2)
+		v, ok := labels[kv[0]]
+		if ok && (len(kv) == 1 || v == kv[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+func diskUsagePercent(path string) (float64, error) {
+	used, total, err := diskUsage(path)
+	if err != nil || total == 0 {
+		return 0, err
+	}
+	return float64(used) / float64(total) * 100, nil
+}
+
+// UpdateLastUsed sets the last used time of the image to now. It's called
+// when a container using the image is created or started.
+func (i *ImageService) UpdateLastUsed(id image.ID) {
+	if id == "" {
+		return
+	}
+	if err := i.imageStore.SetLastUsed(id); err != nil {
+		logrus.WithError(err).WithField("image", id).Warn("failed to set the last used time of the image")
+	}
+}
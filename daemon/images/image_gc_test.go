@@ -0,0 +1,140 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/container"
+	daemonevents "github.com/docker/docker/daemon/events"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	dockerreference "github.com/docker/docker/reference"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+type fakeContainerStore struct {
+	containers []*container.Container
+}
+
+func (s *fakeContainerStore) First(filter container.StoreFilter) *container.Container {
+	for _, c := range s.containers {
+		if filter(c) {
+			return c
+		}
+	}
+	return nil
+}
+
+func (s *fakeContainerStore) List() []*container.Container {
+	return s.containers
+}
+
+func (s *fakeContainerStore) Get(id string) *container.Container {
+	return nil
+}
+
+type fakeLayerGetReleaser struct{}
+
+func (fakeLayerGetReleaser) Get(layer.ChainID) (layer.Layer, error) {
+	return nil, layer.ErrLayerDoesNotExist
+}
+
+func (fakeLayerGetReleaser) Release(layer.Layer) ([]layer.Metadata, error) {
+	return nil, nil
+}
+
+func TestCollectImages(t *testing.T) {
+	root, err := ioutil.TempDir("", "image-gc-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	fs, err := image.NewFSStoreBackend(filepath.Join(root, "images"))
+	assert.NilError(t, err)
+	imageStore, err := image.NewImageStore(fs, map[string]image.LayerGetReleaser{runtime.GOOS: fakeLayerGetReleaser{}})
+	assert.NilError(t, err)
+	referenceStore, err := dockerreference.NewReferenceStore(filepath.Join(root, "repositories.json"))
+	assert.NilError(t, err)
+	containers := &fakeContainerStore{}
+	events := daemonevents.New()
+	i := &ImageService{
+		containers:     containers,
+		eventsService:  events,
+		imageStore:     imageStore,
+		referenceStore: referenceStore,
+	}
+
+	create := func(config string) image.ID {
+		id, err := imageStore.Create([]byte(config))
+		assert.NilError(t, err)
+		// the last used times must be ordered
+		time.Sleep(10 * time.Millisecond)
+		return id
+	}
+	recent := create(`{"comment": "recent", "rootfs": {"type": "layers"}}`)
+	tagged := create(`{"comment": "tagged", "rootfs": {"type": "layers"}}`)
+	excluded := create(`{"comment": "excluded", "config": {"Labels": {"com.example.keep": ""}}, "rootfs": {"type": "layers"}}`)
+	used := create(`{"comment": "used", "rootfs": {"type": "layers"}}`)
+	i.UpdateLastUsed(recent)
+
+	ref, err := reference.ParseNormalizedNamed("example/tagged:latest")
+	assert.NilError(t, err)
+	assert.NilError(t, referenceStore.AddTag(ref, tagged.Digest(), false))
+	containers.containers = []*container.Container{{ImageID: used, State: container.NewState()}}
+
+	// each image uses 10% of the disk
+	defer func(orig func(string) (uint64, uint64, error)) { diskUsage = orig }(diskUsage)
+	diskUsage = func(string) (uint64, uint64, error) {
+		return uint64(60 + 10*imageStore.Len()), 100, nil
+	}
+
+	policy := GCPolicy{
+		Root:          root,
+		HighWatermark: 95,
+		LowWatermark:  90,
+		ExcludeLabels: []string{"com.example.keep"},
+	}
+	i.collectImages(policy)
+
+	// the least recently used image is deleted first, down to the low watermark
+	assert.Check(t, is.Equal(imageStore.Len(), 3))
+	_, err = imageStore.Get(tagged)
+	assert.Check(t, is.ErrorContains(err, ""))
+	assert.Check(t, is.Len(referenceStore.References(tagged.Digest()), 0))
+
+	msgs, _, cancel := events.Subscribe()
+	cancel()
+	var gcEvents []string
+	for _, m := range msgs {
+		if m.Action == "gc" {
+			gcEvents = append(gcEvents, m.Actor.ID+" "+m.Actor.Attributes["name"])
+		}
+	}
+	assert.Check(t, is.DeepEqual(gcEvents, []string{tagged.String() + " example/tagged:latest"}))
+
+	// below the high watermark, nothing is deleted
+	i.collectImages(policy)
+	assert.Check(t, is.Equal(imageStore.Len(), 3))
+
+	// the excluded and used images are never deleted
+	policy.HighWatermark, policy.LowWatermark = 10, 5
+	i.collectImages(policy)
+	assert.Check(t, is.Equal(imageStore.Len(), 2))
+	for _, id := range []image.ID{excluded, used} {
+		_, err := imageStore.Get(id)
+		assert.Check(t, err)
+	}
+}
+
+func TestHasExcludedLabel(t *testing.T) {
+	labels := map[string]string{"com.example.keep": "", "com.example.team": "ci"}
+	assert.Check(t, hasExcludedLabel(labels, []string{"com.example.keep"}))
+	assert.Check(t, hasExcludedLabel(labels, []string{"other", "com.example.team=ci"}))
+	assert.Check(t, !hasExcludedLabel(labels, []string{"com.example.team=web"}))
+	assert.Check(t, !hasExcludedLabel(labels, nil))
+}
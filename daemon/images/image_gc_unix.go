@@ -0,0 +1,17 @@
+// +build linux freebsd
+
+package images // import "github.com/docker/docker/daemon/images"
+
+import "golang.org/x/sys/unix"
+
+func getDiskUsage(path string) (used, total uint64, err error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return 0, 0, err
+	}
+	// like df, the blocks reserved to root are not counted
+	bsize := uint64(st.Bsize)
+	used = (uint64(st.Blocks) - uint64(st.Bfree)) * bsize
+	total = used + uint64(st.Bavail)*bsize
+	return used, total, nil
+}
@@ -0,0 +1,26 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procGetDiskFreeSpaceExW = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+func getDiskUsage(path string) (used, total uint64, err error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	var freeAvailable, totalBytes, free uint64
+	r, _, err := procGetDiskFreeSpaceExW.Call(
+		uintptr(unsafe.Pointer(p)),
+		uintptr(unsafe.Pointer(&freeAvailable)),
+		uintptr(unsafe.Pointer(&totalBytes)),
+		uintptr(unsafe.Pointer(&free)))
+	if r == 0 {
+		return 0, 0, err
+	}
+	return totalBytes - free, totalBytes, nil
+}
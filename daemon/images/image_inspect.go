@@ -83,6 +83,7 @@ func (i *ImageService) LookupImage(name string) (*types.ImageInspect, error) {
 		RootFS:          rootFSToAPIType(img.RootFS),
 		Metadata: types.ImageMetadata{
 			LastTagTime: lastUpdated,
+			Protected:   i.imageStore.IsProtected(img.ID()),
 		},
 	}
 
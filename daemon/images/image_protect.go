@@ -0,0 +1,20 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+// ImageSetProtected sets whether the image named imageName is protected. A
+// protected image is skipped by prune and by the image GC, and can only be
+// deleted with force.
+func (i *ImageService) ImageSetProtected(imageName string, protected bool) error {
+	img, err := i.GetImage(imageName)
+	if err != nil {
+		return err
+	}
+	if err := i.imageStore.SetProtected(img.ID(), protected); err != nil {
+		return err
+	}
+	action := "protect"
+	if !protected {
+		action = "unprotect"
+	}
+	i.LogImageEvent(img.ID().String(), imageName, action)
+	return nil
+}
@@ -88,6 +88,9 @@ func (i *ImageService) ImagesPrune(ctx context.Context, pruneFilters filters.Arg
 			if img.Config != nil && !matchLabels(pruneFilters, img.Config.Labels) {
 				continue
 			}
+			if i.imageStore.IsProtected(id) {
+				continue
+			}
 			topImages[id] = img
 		}
 	}
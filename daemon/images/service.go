@@ -120,6 +120,7 @@ func (i *ImageService) CreateLayer(container *container.Container, initFunc laye
 			return nil, err
 		}
 		layerID = img.RootFS.ChainID()
+		i.UpdateLastUsed(container.ImageID)
 	}
 
 	rwLayerOpts := &layer.CreateRWLayerOpts{
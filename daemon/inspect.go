@@ -144,21 +144,29 @@ func (daemon *Daemon) getInspectData(container *container.Container) (*types.Con
 			FailingStreak: container.State.Health.FailingStreak,
 			Log:           append([]*types.HealthcheckResult{}, container.State.Health.Log...),
 		}
+		if readiness := container.State.Health.Readiness; readiness != nil {
+			containerHealth.Readiness = &types.Readiness{
+				Status:        container.State.Health.ReadinessStatus(),
+				FailingStreak: readiness.FailingStreak,
+				Log:           append([]*types.HealthcheckResult{}, readiness.Log...),
+			}
+		}
 	}
 
 	containerState := &types.ContainerState{
-		Status:     container.State.StateString(),
-		Running:    container.State.Running,
-		Paused:     container.State.Paused,
-		Restarting: container.State.Restarting,
-		OOMKilled:  container.State.OOMKilled,
-		Dead:       container.State.Dead,
-		Pid:        container.State.Pid,
-		ExitCode:   container.State.ExitCode(),
-		Error:      container.State.ErrorMsg,
-		StartedAt:  container.State.StartedAt.Format(time.RFC3339Nano),
-		FinishedAt: container.State.FinishedAt.Format(time.RFC3339Nano),
-		Health:     containerHealth,
+		Status:       container.State.StateString(),
+		Running:      container.State.Running,
+		Paused:       container.State.Paused,
+		Restarting:   container.State.Restarting,
+		CrashLooping: container.State.CrashLooping,
+		OOMKilled:    container.State.OOMKilled,
+		Dead:         container.State.Dead,
+		Pid:          container.State.Pid,
+		ExitCode:     container.State.ExitCode(),
+		Error:        container.State.ErrorMsg,
+		StartedAt:    container.State.StartedAt.Format(time.RFC3339Nano),
+		FinishedAt:   container.State.FinishedAt.Format(time.RFC3339Nano),
+		Health:       containerHealth,
 	}
 
 	contJSONBase := &types.ContainerJSONBase{
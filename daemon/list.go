@@ -33,6 +33,16 @@ var acceptedPsFilterTags = map[string]bool{
 	"is-task":   true,
 	"publish":   true,
 	"expose":    true,
+	"attr":      true,
+
+	// negated filters, such as "name!=web", exclude the containers that
+	// the corresponding filter would include
+	"name!":   true,
+	"id!":     true,
+	"label!":  true,
+	"status!": true,
+	"health!": true,
+	"attr!":   true,
 }
 
 // iterationAction represents possible outcomes happening during the container iteration.
@@ -147,7 +157,7 @@ func (daemon *Daemon) filterByNameIDMatches(view container.View, ctx *listContex
 			}
 			for _, eachName := range idNames {
 				// match both on container name with, and without slash-prefix
-				if ctx.filters.Match("name", eachName) || ctx.filters.Match("name", strings.TrimPrefix(eachName, "/")) {
+				if matchName(ctx.filters, "name", eachName) {
 					matches[id] = true
 				}
 			}
@@ -179,6 +189,11 @@ func (daemon *Daemon) reduceContainers(config *types.ContainerListOptions, reduc
 	if err := config.Filters.Validate(acceptedPsFilterTags); err != nil {
 		return nil, err
 	}
+	for _, key := range []string{"attr", "attr!"} {
+		if err := config.Filters.ValidateAttrs(key); err != nil {
+			return nil, err
+		}
+	}
 
 	var (
 		view       = daemon.containersReplica.Snapshot()
@@ -405,6 +420,64 @@ func portOp(key string, filter map[nat.Port]bool) func(value string) error {
 	}
 }
 
+// matchName returns true if the name of the container matches one of the
+// regular expressions or shell patterns at key, or if there are none.
+func matchName(psFilters filters.Args, key, name string) bool {
+	trimmed := strings.TrimPrefix(name, "/")
+	return psFilters.Match(key, name) || psFilters.Match(key, trimmed) || psFilters.GlobMatch(key, trimmed)
+}
+
+// matchNegatedFilters returns true if the container matches any of the
+// negated filters.
+func matchNegatedFilters(container *container.Snapshot, psFilters filters.Args) bool {
+	if psFilters.Contains("name!") && matchName(psFilters, "name!", container.Name) {
+		return true
+	}
+	if psFilters.Contains("id!") && psFilters.Match("id!", container.ID) {
+		return true
+	}
+	if psFilters.Contains("status!") && psFilters.Match("status!", container.State) {
+		return true
+	}
+	if psFilters.Contains("health!") && psFilters.ExactMatch("health!", container.Health) {
+		return true
+	}
+	for _, label := range psFilters.Get("label!") {
+		if filters.NewArgs(filters.Arg("label", label)).MatchKVList("label", container.Labels) {
+			return true
+		}
+	}
+	if psFilters.Contains("attr!") {
+		attrs := containerAttributes(container)
+		for _, expr := range psFilters.Get("attr!") {
+			if filters.NewArgs(filters.Arg("attr", expr)).MatchAttrs("attr", attrs) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containerAttributes returns the attributes the "attr" filter matches
+// against. They mirror the attributes of container events: the labels of
+// the container, its name and image, and the exit code once it exited.
+func containerAttributes(container *container.Snapshot) map[string]string {
+	attrs := make(map[string]string, len(container.Labels)+5)
+	for k, v := range container.Labels {
+		attrs[k] = v
+	}
+	attrs["name"] = strings.TrimPrefix(container.Name, "/")
+	attrs["image"] = container.Image
+	attrs["status"] = container.State
+	if container.Health != "" {
+		attrs["health"] = container.Health
+	}
+	if !container.Running && !container.StartedAt.IsZero() {
+		attrs["exitCode"] = strconv.Itoa(container.ExitCode)
+	}
+	return attrs
+}
+
 // includeContainerInList decides whether a container should be included in the output or not based in the filter.
 // It also decides if the iteration should be stopped or not.
 func includeContainerInList(container *container.Snapshot, ctx *listContext) iterationAction {
@@ -430,7 +503,7 @@ func includeContainerInList(container *container.Snapshot, ctx *listContext) ite
 	}
 
 	// Do not include container if the name doesn't match
-	if !ctx.filters.Match("name", container.Name) && !ctx.filters.Match("name", strings.TrimPrefix(container.Name, "/")) {
+	if !matchName(ctx.filters, "name", container.Name) {
 		return excludeContainer
 	}
 
@@ -484,6 +557,16 @@ func includeContainerInList(container *container.Snapshot, ctx *listContext) ite
 		return excludeContainer
 	}
 
+	// Do not include container if any of the attribute expressions doesn't hold
+	if ctx.filters.Contains("attr") && !ctx.filters.MatchAttrs("attr", containerAttributes(container)) {
+		return excludeContainer
+	}
+
+	// Do not include container if any of the negated filters matches
+	if matchNegatedFilters(container, ctx.filters) {
+		return excludeContainer
+	}
+
 	if ctx.filters.Contains("volume") {
 		volumesByName := make(map[string]types.MountPoint)
 		for _, m := range container.Mounts {
@@ -143,3 +143,74 @@ func TestNameFilter(t *testing.T) {
 	assert.Assert(t, is.Len(containerListWithPrefix, 1))
 	assert.Assert(t, containerListContainsName(containerListWithPrefix, three.Name))
 }
+
+func TestGlobAndNegatedFilters(t *testing.T) {
+	db, err := container.NewViewDB()
+	assert.Assert(t, err == nil)
+	d := &Daemon{
+		containersReplica: db,
+	}
+
+	var (
+		one   = setupContainerWithName(t, "a1", d)
+		two   = setupContainerWithName(t, "a2", d)
+		three = setupContainerWithName(t, "b1", d)
+	)
+
+	containerList, err := d.Containers(&types.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("name", "?1")),
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(containerList, 2))
+	assert.Assert(t, containerListContainsName(containerList, one.Name))
+	assert.Assert(t, containerListContainsName(containerList, three.Name))
+
+	containerList, err = d.Containers(&types.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("name!", "a1")),
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(containerList, 2))
+	assert.Assert(t, containerListContainsName(containerList, two.Name))
+	assert.Assert(t, containerListContainsName(containerList, three.Name))
+
+	containerList, err = d.Containers(&types.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("name", "^a"), filters.Arg("name!", "*2")),
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(containerList, 1))
+	assert.Assert(t, containerListContainsName(containerList, one.Name))
+}
+
+func TestAttrFilter(t *testing.T) {
+	db, err := container.NewViewDB()
+	assert.Assert(t, err == nil)
+	d := &Daemon{
+		containersReplica: db,
+	}
+
+	one := setupContainerWithName(t, "front1", d)
+	one.Config.Labels = map[string]string{"tier": "frontend", "replicas": "3"}
+	assert.NilError(t, d.containersReplica.Save(one))
+	two := setupContainerWithName(t, "back1", d)
+	two.Config.Labels = map[string]string{"tier": "backend", "replicas": "1"}
+	assert.NilError(t, d.containersReplica.Save(two))
+
+	containerList, err := d.Containers(&types.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("attr", "replicas>1")),
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(containerList, 1))
+	assert.Assert(t, containerListContainsName(containerList, one.Name))
+
+	containerList, err = d.Containers(&types.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("attr", "tier!=front*")),
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(containerList, 1))
+	assert.Assert(t, containerListContainsName(containerList, two.Name))
+
+	_, err = d.Containers(&types.ContainerListOptions{
+		Filters: filters.NewArgs(filters.Arg("attr", "replicas>many")),
+	})
+	assert.Assert(t, is.Error(err, "Invalid filter 'replicas>many'"))
+}
@@ -2,7 +2,9 @@ package logger // import "github.com/docker/docker/daemon/logger"
 
 import (
 	"bytes"
+	"fmt"
 	"io"
+	"sort"
 	"sync"
 	"time"
 
@@ -27,7 +29,11 @@ type Copier struct {
 	// srcs is map of name -> reader pairs, for example "stdout", "stderr"
 	srcs      map[string]io.Reader
 	dst       Logger
+	limiter   *RateLimiter
+	multiline *MultilineConfig
+	parser    *LineParser
 	copyJobs  sync.WaitGroup
+	reportJob sync.WaitGroup
 	closeOnce sync.Once
 	closed    chan struct{}
 }
@@ -41,12 +47,84 @@ func NewCopier(srcs map[string]io.Reader, dst Logger) *Copier {
 	}
 }
 
+// SetRateLimiter sets the rate limit of the messages copied to the Logger.
+// It must be called before Run.
+func (c *Copier) SetRateLimiter(limiter *RateLimiter) {
+	c.limiter = limiter
+}
+
+// SetMultiline sets the configuration of the aggregation of multiline log
+// entries. It must be called before Run.
+func (c *Copier) SetMultiline(cfg *MultilineConfig) {
+	c.multiline = cfg
+}
+
+// SetParser sets the parser of the lines copied to the Logger. It must be
+// called before Run.
+func (c *Copier) SetParser(p *LineParser) {
+	c.parser = p
+}
+
 // Run starts logs copying
 func (c *Copier) Run() {
 	for src, w := range c.srcs {
 		c.copyJobs.Add(1)
 		go c.copySrc(src, w)
 	}
+	if c.limiter != nil {
+		done := make(chan struct{})
+		go func() {
+			c.copyJobs.Wait()
+			close(done)
+		}()
+		c.reportJob.Add(1)
+		go c.reportSuppressed(done)
+	}
+}
+
+// allow reports whether a message of size bytes from src is within the rate
+// limit.
+func (c *Copier) allow(src string, size int) bool {
+	return c.limiter == nil || c.limiter.Allow(src, size)
+}
+
+// reportSuppressed periodically logs the number of messages dropped by the
+// rate limit, until all sources are copied.
+func (c *Copier) reportSuppressed(done <-chan struct{}) {
+	defer c.reportJob.Done()
+
+	ticker := time.NewTicker(suppressedReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.closed:
+			return
+		case <-done:
+			c.logSuppressed()
+			return
+		case <-ticker.C:
+			c.logSuppressed()
+		}
+	}
+}
+
+func (c *Copier) logSuppressed() {
+	suppressed := c.limiter.suppressedMessages()
+	srcs := make([]string, 0, len(suppressed))
+	for src := range suppressed {
+		srcs = append(srcs, src)
+	}
+	sort.Strings(srcs)
+	for _, src := range srcs {
+		msg := NewMessage()
+		msg.Source = src
+		msg.Timestamp = time.Now().UTC()
+		msg.Line = append(msg.Line, fmt.Sprintf("%d messages suppressed by the log rate limit", suppressed[src])...)
+		if logErr := c.dst.Log(msg); logErr != nil {
+			logWritesFailedCount.Inc(1)
+			logrus.Errorf("Failed to log msg %q for logger %s: %s", msg.Line, c.dst.Name(), logErr)
+		}
+	}
 }
 
 func (c *Copier) copySrc(name string, src io.Reader) {
@@ -65,6 +143,11 @@ func (c *Copier) copySrc(name string, src io.Reader) {
 	var ordinal int
 	firstPartial := true
 	hasMorePartial := false
+	out := &srcLogger{c: c, name: name}
+	if c.multiline != nil {
+		out.multiline = newMultilineAggregator(c.multiline, bufSize, out.write)
+		defer out.multiline.close()
+	}
 
 	for {
 		select {
@@ -120,10 +203,7 @@ func (c *Copier) copySrc(name string, src io.Reader) {
 						msg.Timestamp = partialTS
 					}
 
-					if logErr := c.dst.Log(msg); logErr != nil {
-						logWritesFailedCount.Inc(1)
-						logrus.Errorf("Failed to log msg %q for logger %s: %s", msg.Line, c.dst.Name(), logErr)
-					}
+					out.log(msg)
 				}
 				p += q + 1
 			}
@@ -153,10 +233,7 @@ func (c *Copier) copySrc(name string, src io.Reader) {
 					ordinal++
 					hasMorePartial = true
 
-					if logErr := c.dst.Log(msg); logErr != nil {
-						logWritesFailedCount.Inc(1)
-						logrus.Errorf("Failed to log msg %q for logger %s: %s", msg.Line, c.dst.Name(), logErr)
-					}
+					out.log(msg)
 					p = 0
 					n = 0
 				}
@@ -173,9 +250,53 @@ func (c *Copier) copySrc(name string, src io.Reader) {
 	}
 }
 
+// srcLogger logs the messages copied from a source, aggregating multiline
+// entries and applying the rate limit if they are configured.
+type srcLogger struct {
+	c         *Copier
+	name      string
+	multiline *multilineAggregator
+	// dropPartial is set when the partial message being logged is over the
+	// rate limit, so that all its parts are dropped
+	dropPartial bool
+}
+
+func (l *srcLogger) log(msg *Message) {
+	if l.multiline != nil {
+		l.multiline.add(msg)
+		return
+	}
+	l.write(msg)
+}
+
+// write logs msg to the Logger, unless it's over the rate limit.
+func (l *srcLogger) write(msg *Message) {
+	if l.c.parser != nil {
+		l.c.parser.Parse(msg)
+	}
+
+	drop := l.dropPartial
+	switch {
+	case msg.PLogMetaData == nil:
+		drop = !l.c.allow(l.name, len(msg.Line))
+	case msg.PLogMetaData.Ordinal == 1:
+		l.dropPartial = !l.c.allow(l.name, len(msg.Line))
+		drop = l.dropPartial
+	}
+	if drop {
+		PutMessage(msg)
+		return
+	}
+	if logErr := l.c.dst.Log(msg); logErr != nil {
+		logWritesFailedCount.Inc(1)
+		logrus.Errorf("Failed to log msg %q for logger %s: %s", msg.Line, l.c.dst.Name(), logErr)
+	}
+}
+
 // Wait waits until all copying is done
 func (c *Copier) Wait() {
 	c.copyJobs.Wait()
+	c.reportJob.Wait()
 }
 
 // Close closes the copier
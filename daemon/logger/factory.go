@@ -116,9 +116,41 @@ func GetLogDriver(name string) (Creator, error) {
 	return factory.get(name)
 }
 
+var (
+	externalValidatorsMu sync.Mutex
+	externalValidators   []LogOptValidator
+)
+
+// RegisterExternalValidator adds a validator of the log options of all the
+// logging drivers. It is used by packages supplementing the log options
+// supported by every driver, along with AddBuiltinLogOpts.
+func RegisterExternalValidator(v LogOptValidator) {
+	externalValidatorsMu.Lock()
+	externalValidators = append(externalValidators, v)
+	externalValidatorsMu.Unlock()
+}
+
+// AddBuiltinLogOpts adds log options supported by every logging driver. The
+// options are not passed to the validators of the drivers.
+func AddBuiltinLogOpts(opts map[string]bool) {
+	externalValidatorsMu.Lock()
+	for k, v := range opts {
+		builtInLogOpts[k] = v
+	}
+	externalValidatorsMu.Unlock()
+}
+
 var builtInLogOpts = map[string]bool{
-	"mode":            true,
-	"max-buffer-size": true,
+	"mode":                 true,
+	"max-buffer-size":      true,
+	rateLimitLinesOpt:      true,
+	rateLimitLinesBurstOpt: true,
+	rateLimitBytesOpt:      true,
+	rateLimitBytesBurstOpt: true,
+	multilinePatternOpt:    true,
+	multilineTimeoutOpt:    true,
+	parseOpt:               true,
+	parseFieldsOpt:         true,
 }
 
 // ValidateLogOpts checks the options for the given log driver. The
@@ -143,16 +175,37 @@ func ValidateLogOpts(name string, cfg map[string]string) error {
 		}
 	}
 
+	if err := validateRateLimitOpts(cfg); err != nil {
+		return errors.Wrap(err, "logger")
+	}
+	if err := validateMultilineOpts(cfg); err != nil {
+		return errors.Wrap(err, "logger")
+	}
+	if err := validateParseOpts(cfg); err != nil {
+		return errors.Wrap(err, "logger")
+	}
+
+	externalValidatorsMu.Lock()
+	validators := externalValidators
+	externalValidatorsMu.Unlock()
+	for _, validator := range validators {
+		if err := validator(cfg); err != nil {
+			return err
+		}
+	}
+
 	if !factory.driverRegistered(name) {
 		return fmt.Errorf("logger: no log driver named '%s' is registered", name)
 	}
 
 	filteredOpts := make(map[string]string, len(builtInLogOpts))
+	externalValidatorsMu.Lock()
 	for k, v := range cfg {
 		if !builtInLogOpts[k] {
 			filteredOpts[k] = v
 		}
 	}
+	externalValidatorsMu.Unlock()
 
 	validator := factory.getLogOptValidator(name)
 	if validator != nil {
@@ -0,0 +1,78 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"regexp"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+// MessageFilter matches messages against the filters of a ReadConfig.
+type MessageFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+	source  string
+	attrs   map[string]string
+}
+
+// NewMessageFilter compiles the filters of config. It returns nil if config
+// has no filters, which matches all messages.
+func NewMessageFilter(config ReadConfig) (*MessageFilter, error) {
+	if len(config.Include) == 0 && len(config.Exclude) == 0 && config.Source == "" && len(config.Attrs) == 0 {
+		return nil, nil
+	}
+	f := &MessageFilter{source: config.Source, attrs: config.Attrs}
+	for _, expr := range config.Include {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, errdefs.InvalidParameter(errors.Wrap(err, "invalid include pattern"))
+		}
+		f.include = append(f.include, re)
+	}
+	for _, expr := range config.Exclude {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, errdefs.InvalidParameter(errors.Wrap(err, "invalid exclude pattern"))
+		}
+		f.exclude = append(f.exclude, re)
+	}
+	return f, nil
+}
+
+// Match returns true if msg matches all the filters.
+func (f *MessageFilter) Match(msg *Message) bool {
+	if f == nil {
+		return true
+	}
+	if f.source != "" && msg.Source != f.source {
+		return false
+	}
+	for k, v := range f.attrs {
+		if !hasAttr(msg, k, v) {
+			return false
+		}
+	}
+	for _, re := range f.exclude {
+		if re.Match(msg.Line) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, re := range f.include {
+		if re.Match(msg.Line) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAttr(msg *Message, key, value string) bool {
+	for _, attr := range msg.Attrs {
+		if attr.Key == key && attr.Value == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/errdefs"
+)
+
+func TestMessageFilter(t *testing.T) {
+	msgs := []*Message{
+		{Source: "stdout", Line: []byte("GET /index.html 200")},
+		{Source: "stdout", Line: []byte("GET /healthz 200")},
+		{Source: "stderr", Line: []byte("error: connection refused"), Attrs: []backend.LogAttr{{Key: "app", Value: "web"}}},
+		{Source: "stdout", Line: []byte("POST /login 500"), Attrs: []backend.LogAttr{{Key: "app", Value: "web"}}},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		config ReadConfig
+		expect []int
+	}{
+		{name: "no filters", expect: []int{0, 1, 2, 3}},
+		{name: "include", config: ReadConfig{Include: []string{"^GET", "refused"}}, expect: []int{0, 1, 2}},
+		{name: "exclude", config: ReadConfig{Exclude: []string{"healthz"}}, expect: []int{0, 2, 3}},
+		{name: "include and exclude", config: ReadConfig{Include: []string{"^GET"}, Exclude: []string{"healthz"}}, expect: []int{0}},
+		{name: "source", config: ReadConfig{Source: "stderr"}, expect: []int{2}},
+		{name: "attrs", config: ReadConfig{Attrs: map[string]string{"app": "web"}}, expect: []int{2, 3}},
+		{name: "attrs mismatch", config: ReadConfig{Attrs: map[string]string{"app": "db"}}},
+		{name: "attrs and source", config: ReadConfig{Source: "stdout", Attrs: map[string]string{"app": "web"}}, expect: []int{3}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := NewMessageFilter(tc.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var matched []int
+			for i, msg := range msgs {
+				if f.Match(msg) {
+					matched = append(matched, i)
+				}
+			}
+			if len(matched) != len(tc.expect) {
+				t.Fatalf("expected messages %v to match, got %v", tc.expect, matched)
+			}
+			for i := range matched {
+				if matched[i] != tc.expect[i] {
+					t.Fatalf("expected messages %v to match, got %v", tc.expect, matched)
+				}
+			}
+		})
+	}
+}
+
+func TestMessageFilterInvalid(t *testing.T) {
+	for _, config := range []ReadConfig{
+		{Include: []string{"("}},
+		{Exclude: []string{"[a-"}},
+	} {
+		_, err := NewMessageFilter(config)
+		if !errdefs.IsInvalidParameter(err) {
+			t.Fatalf("expected an invalid parameter error for %+v, got %v", config, err)
+		}
+	}
+}
@@ -163,6 +163,11 @@ func (f *fluentd) Log(msg *logger.Message) error {
 	for k, v := range f.extra {
 		data[k] = v
 	}
+	for _, attr := range msg.Attrs {
+		if _, ok := data[attr.Key]; !ok {
+			data[attr.Key] = attr.Value
+		}
+	}
 	if msg.PLogMetaData != nil {
 		data["partial_message"] = "true"
 		data["partial_id"] = msg.PLogMetaData.ID
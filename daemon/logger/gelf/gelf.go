@@ -24,6 +24,7 @@ type gelfLogger struct {
 	writer   gelf.Writer
 	info     logger.Info
 	hostname string
+	extra    map[string]interface{}
 	rawExtra json.RawMessage
 }
 
@@ -104,6 +105,7 @@ func New(info logger.Info) (logger.Logger, error) {
 		writer:   gelfWriter,
 		info:     info,
 		hostname: hostname,
+		extra:    extra,
 		rawExtra: rawExtra,
 	}, nil
 }
@@ -171,13 +173,18 @@ func (s *gelfLogger) Log(msg *logger.Message) error {
 		level = gelf.LOG_ERR
 	}
 
+	rawExtra, err := s.messageExtra(msg)
+	if err != nil {
+		return fmt.Errorf("gelf: cannot marshal message attributes: %v", err)
+	}
+
 	m := gelf.Message{
 		Version:  "1.1",
 		Host:     s.hostname,
 		Short:    string(msg.Line),
 		TimeUnix: float64(msg.Timestamp.UnixNano()/int64(time.Millisecond)) / 1000.0,
 		Level:    int32(level),
-		RawExtra: s.rawExtra,
+		RawExtra: rawExtra,
 	}
 	logger.PutMessage(msg)
 
@@ -187,6 +194,26 @@ func (s *gelfLogger) Log(msg *logger.Message) error {
 	return nil
 }
 
+// messageExtra returns the additional fields of the GELF message of msg,
+// which are the attributes of the message on top of the ones of the logger.
+func (s *gelfLogger) messageExtra(msg *logger.Message) (json.RawMessage, error) {
+	if len(msg.Attrs) == 0 {
+		return s.rawExtra, nil
+	}
+	extra := make(map[string]interface{}, len(s.extra)+len(msg.Attrs))
+	for k, v := range s.extra {
+		extra[k] = v
+	}
+	for _, attr := range msg.Attrs {
+		if attr.Key == "id" {
+			// _id is reserved by GELF
+			continue
+		}
+		extra["_"+attr.Key] = attr.Value
+	}
+	return json.Marshal(extra)
+}
+
 func (s *gelfLogger) Close() error {
 	return s.writer.Close()
 }
@@ -109,6 +109,11 @@ func (s *journald) Log(msg *logger.Message) error {
 	if msg.PLogMetaData != nil && !msg.PLogMetaData.Last {
 		vars["CONTAINER_PARTIAL_MESSAGE"] = "true"
 	}
+	for _, attr := range msg.Attrs {
+		if key := sanitizeKeyMod(attr.Key); key != "" {
+			vars[key] = attr.Value
+		}
+	}
 
 	line := string(msg.Line)
 	source := msg.Source
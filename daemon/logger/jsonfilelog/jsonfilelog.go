@@ -102,6 +102,22 @@ func New(info logger.Info) (logger.Logger, error) {
 
 	buf := bytes.NewBuffer(nil)
 	marshalFunc := func(msg *logger.Message) ([]byte, error) {
+		extra := extra
+		if len(msg.Attrs) > 0 {
+			// the attributes of the message are stored along with the ones
+			// of the logger
+			merged := make(map[string]string, len(attrs)+len(msg.Attrs))
+			for k, v := range attrs {
+				merged[k] = v
+			}
+			for _, attr := range msg.Attrs {
+				merged[attr.Key] = attr.Value
+			}
+			var err error
+			if extra, err = json.Marshal(merged); err != nil {
+				return nil, err
+			}
+		}
 		if err := marshalMessage(msg, extra, buf); err != nil {
 			return nil, err
 		}
@@ -110,7 +126,12 @@ func New(info logger.Info) (logger.Logger, error) {
 		return b, nil
 	}
 
-	writer, err := loggerutils.NewLogFile(info.LogPath, capval, maxFiles, compress, marshalFunc, decodeFunc, 0640, getTailReader)
+	compression := loggerutils.CompressionNone
+	if compress {
+		compression = loggerutils.CompressionGzip
+	}
+
+	writer, err := loggerutils.NewLogFile(info.LogPath, capval, maxFiles, compression, marshalFunc, decodeFunc, 0640, getTailReader)
 	if err != nil {
 		return nil, err
 	}
@@ -9,10 +9,12 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strconv"
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/daemon/logger/jsonfilelog/jsonlog"
 	"gotest.tools/assert"
@@ -318,3 +320,40 @@ func TestJSONFileLoggerWithLabelsEnv(t *testing.T) {
 		t.Fatalf("Wrong log attrs: %q, expected %q", extra, expected)
 	}
 }
+
+func TestJSONFileLoggerWithMessageAttrs(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "docker-logger-")
+	assert.NilError(t, err)
+	defer os.RemoveAll(tmp)
+
+	l, err := New(logger.Info{
+		LogPath:         filepath.Join(tmp, "container.log"),
+		Config:          map[string]string{"labels": "rack"},
+		ContainerLabels: map[string]string{"rack": "101"},
+	})
+	assert.NilError(t, err)
+	defer l.Close()
+
+	for _, level := range []string{"info", "error"} {
+		msg := &logger.Message{Line: []byte("line"), Source: "stdout", Timestamp: time.Now()}
+		msg.Attrs = append(msg.Attrs, backend.LogAttr{Key: "level", Value: level})
+		assert.NilError(t, l.Log(msg))
+	}
+
+	lw := l.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: -1, Attrs: map[string]string{"level": "error"}})
+	defer lw.ConsumerGone()
+	select {
+	case msg := <-lw.Msg:
+		sort.Slice(msg.Attrs, func(i, j int) bool { return msg.Attrs[i].Key < msg.Attrs[j].Key })
+		assert.Check(t, is.DeepEqual(msg.Attrs, []backend.LogAttr{{Key: "level", Value: "error"}, {Key: "rack", Value: "101"}}))
+	case err := <-lw.Err:
+		t.Fatal(err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for log message")
+	}
+	select {
+	case msg, ok := <-lw.Msg:
+		assert.Check(t, !ok, "unexpected message %v", msg)
+	case <-time.After(time.Second):
+	}
+}
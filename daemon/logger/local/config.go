@@ -1,12 +1,14 @@
 package local
 
 import (
+	"github.com/docker/docker/daemon/logger/loggerutils"
 	"github.com/pkg/errors"
 )
 
 // CreateConfig is used to configure new instances of driver
 type CreateConfig struct {
 	DisableCompression bool
+	CompressionFormat  string
 	MaxFileSize        int64
 	MaxFileCount       int
 }
@@ -16,6 +18,7 @@ func newDefaultConfig() *CreateConfig {
 		MaxFileSize:        defaultMaxFileSize,
 		MaxFileCount:       defaultMaxFileCount,
 		DisableCompression: !defaultCompressLogs,
+		CompressionFormat:  defaultCompressionFormat,
 	}
 }
 
@@ -31,6 +34,11 @@ func validateConfig(cfg *CreateConfig) error {
 		if cfg.MaxFileCount <= 1 {
 			return errors.New("compression cannot be enabled when max file count is 1")
 		}
+		switch cfg.CompressionFormat {
+		case loggerutils.CompressionGzip, loggerutils.CompressionZstd:
+		default:
+			return errors.Errorf("unsupported compression format: %s", cfg.CompressionFormat)
+		}
 	}
 	return nil
 }
@@ -25,16 +25,18 @@ const (
 	initialBufSize  = 2048
 	maxDecodeRetry  = 20000
 
-	defaultMaxFileSize  int64 = 20 * 1024 * 1024
-	defaultMaxFileCount       = 5
-	defaultCompressLogs       = true
+	defaultMaxFileSize       int64 = 20 * 1024 * 1024
+	defaultMaxFileCount            = 5
+	defaultCompressLogs            = true
+	defaultCompressionFormat       = loggerutils.CompressionGzip
 )
 
 // LogOptKeys are the keys names used for log opts passed in to initialize the driver.
 var LogOptKeys = map[string]bool{
-	"max-file": true,
-	"max-size": true,
-	"compress": true,
+	"max-file":           true,
+	"max-size":           true,
+	"compress":           true,
+	"compression-format": true,
 }
 
 // ValidateLogOpt looks for log driver specific options.
@@ -94,6 +96,10 @@ func New(info logger.Info) (logger.Logger, error) {
 		}
 		cfg.DisableCompression = !compressLogs
 	}
+
+	if format, ok := info.Config["compression-format"]; ok {
+		cfg.CompressionFormat = format
+	}
 	return newDriver(info.LogPath, cfg)
 }
 
@@ -140,7 +146,12 @@ func newDriver(logPath string, cfg *CreateConfig) (logger.Logger, error) {
 		return nil, errdefs.InvalidParameter(err)
 	}
 
-	lf, err := loggerutils.NewLogFile(logPath, cfg.MaxFileSize, cfg.MaxFileCount, !cfg.DisableCompression, makeMarshaller(), decodeFunc, 0640, getTailReader)
+	compression := loggerutils.CompressionNone
+	if !cfg.DisableCompression {
+		compression = cfg.CompressionFormat
+	}
+
+	lf, err := loggerutils.NewLogFile(logPath, cfg.MaxFileSize, cfg.MaxFileCount, compression, makeMarshaller(), decodeFunc, 0640, getTailReader)
 	if err != nil {
 		return nil, err
 	}
@@ -186,6 +197,10 @@ func messageToProto(msg *logger.Message, proto *logdriver.LogEntry, partial *log
 	} else {
 		proto.PartialLogMetadata = nil
 	}
+	proto.Attrs = proto.Attrs[:0]
+	for _, attr := range msg.Attrs {
+		proto.Attrs = append(proto.Attrs, &logdriver.LogAttr{Key: attr.Key, Value: attr.Value})
+	}
 }
 
 func protoToMessage(proto *logdriver.LogEntry) *logger.Message {
@@ -200,6 +215,12 @@ func protoToMessage(proto *logdriver.LogEntry) *logger.Message {
 		md.Ordinal = int(proto.GetPartialLogMetadata().GetOrdinal())
 		msg.PLogMetaData = &md
 	}
+	if len(proto.Attrs) > 0 {
+		msg.Attrs = make([]backend.LogAttr, 0, len(proto.Attrs))
+		for _, attr := range proto.Attrs {
+			msg.Attrs = append(msg.Attrs, backend.LogAttr{Key: attr.Key, Value: attr.Value})
+		}
+	}
 	msg.Line = append(msg.Line[:0], proto.Line...)
 	return msg
 }
@@ -215,4 +236,5 @@ func resetProto(proto *logdriver.LogEntry) {
 		proto.PartialLogMetadata.Ordinal = 0
 	}
 	proto.PartialLogMetadata = nil
+	proto.Attrs = proto.Attrs[:0]
 }
@@ -99,7 +99,7 @@ func TestReadLog(t *testing.T) {
 	m2 := logger.Message{Source: "stdout", Timestamp: time.Now().Add(-1 * 20 * time.Minute), Line: []byte("another message"), PLogMetaData: &backend.PartialLogMetaData{Ordinal: 1, Last: true}}
 	longMessage := []byte("a really long message " + strings.Repeat("a", initialBufSize*2))
 	m3 := logger.Message{Source: "stderr", Timestamp: time.Now().Add(-1 * 10 * time.Minute), Line: longMessage}
-	m4 := logger.Message{Source: "stderr", Timestamp: time.Now().Add(-1 * 10 * time.Minute), Line: []byte("just one more message")}
+	m4 := logger.Message{Source: "stderr", Timestamp: time.Now().Add(-1 * 10 * time.Minute), Line: []byte("just one more message"), Attrs: []backend.LogAttr{{Key: "app", Value: "web"}}}
 
 	// copy the log message because the underlying log writer resets the log message and returns it to a buffer pool
 	err = l.Log(copyLogMessage(&m1))
@@ -170,6 +170,67 @@ func TestReadLog(t *testing.T) {
 		testMessage(t, lw, &m4)
 		testMessage(t, lw, nil) // no more messages
 	})
+
+	t.Run("tail with filters", func(t *testing.T) {
+		lw := lr.ReadLogs(logger.ReadConfig{Tail: 2, Include: []string{"message"}, Exclude: []string{"long"}})
+
+		testMessage(t, lw, &m2)
+		testMessage(t, lw, &m4)
+		testMessage(t, lw, nil) // no more messages
+	})
+
+	t.Run("source and attrs", func(t *testing.T) {
+		lw := lr.ReadLogs(logger.ReadConfig{Tail: -1, Source: "stderr", Attrs: map[string]string{"app": "web"}})
+
+		testMessage(t, lw, &m4)
+		testMessage(t, lw, nil) // no more messages
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		lw := lr.ReadLogs(logger.ReadConfig{Tail: -1, Include: []string{"("}})
+
+		select {
+		case err := <-lw.Err:
+			assert.Check(t, err != nil)
+		case <-time.After(30 * time.Second):
+			t.Fatal("timeout waiting for error")
+		}
+	})
+}
+
+func TestCompressionFormat(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", t.Name())
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	logPath := filepath.Join(dir, "test.log")
+	l, err := New(logger.Info{LogPath: logPath, Config: map[string]string{"compression-format": "zstd", "max-size": "1k"}})
+	assert.NilError(t, err)
+	defer l.Close()
+
+	for i := 0; i < 100; i++ {
+		err := l.Log(copyLogMessage(&logger.Message{Source: "stdout", Timestamp: time.Now(), Line: []byte("a message")}))
+		assert.NilError(t, err)
+	}
+	lw := l.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: -1})
+	defer lw.ConsumerGone()
+	for i := 0; i < 100; i++ {
+		select {
+		case msg := <-lw.Msg:
+			assert.Check(t, is.Equal(string(msg.Line), "a message\n"))
+		case err := <-lw.Err:
+			t.Fatal(err)
+		case <-time.After(30 * time.Second):
+			t.Fatal("timeout waiting for log message")
+		}
+	}
+	_, err = os.Stat(logPath + ".1.zst")
+	assert.NilError(t, err)
+
+	_, err = New(logger.Info{LogPath: logPath, Config: map[string]string{"compression-format": "lz4"}})
+	assert.Check(t, is.ErrorContains(err, "unsupported compression format"))
 }
 
 func BenchmarkLogWrite(b *testing.B) {
@@ -91,6 +91,17 @@ type ReadConfig struct {
 	Until  time.Time
 	Tail   int
 	Follow bool
+
+	// Include and Exclude are regular expressions matched against the
+	// lines of the messages: messages must match at least one of Include,
+	// if any, and none of Exclude.
+	Include []string
+	Exclude []string
+	// Source selects the messages of a single stream ("stdout" or
+	// "stderr"), both streams are read if it's empty.
+	Source string
+	// Attrs are the attributes the messages must have.
+	Attrs map[string]string
 }
 
 // LogReader is the interface for reading log messages for loggers that support reading.
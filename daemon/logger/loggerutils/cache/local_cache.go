@@ -0,0 +1,152 @@
+// Package cache provides a local copy of the logs of the containers using a
+// logging driver which does not support reading logs, so that they can still
+// be read with `docker logs`.
+package cache // import "github.com/docker/docker/daemon/logger/loggerutils/cache"
+
+import (
+	"strconv"
+
+	"github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/local"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DriverName is the name of the driver used for the local cache.
+	DriverName = local.Name
+
+	cachePrefix      = "cache-"
+	cacheDisabledKey = cachePrefix + "disabled"
+)
+
+var builtInCacheLogOpts = map[string]bool{
+	cacheDisabledKey: true,
+}
+
+func init() {
+	for k, v := range local.LogOptKeys {
+		builtInCacheLogOpts[cachePrefix+k] = v
+	}
+	logger.AddBuiltinLogOpts(builtInCacheLogOpts)
+	logger.RegisterExternalValidator(validateLogCacheOpts)
+}
+
+// WithLocalCache wraps the passed in logger with a logger which also writes
+// all the messages to a local cache, and reads the logs from the cache.
+// info.LogPath is the path of the cache.
+func WithLocalCache(l logger.Logger, info logger.Info) (logger.Logger, error) {
+	initLogger, err := logger.GetLogDriver(DriverName)
+	if err != nil {
+		return nil, err
+	}
+
+	cacher, err := initLogger(cacheInfo(info))
+	if err != nil {
+		return nil, errors.Wrap(err, "error initializing local log cache driver")
+	}
+
+	return &loggerWithCache{
+		l:     l,
+		cache: cacher,
+	}, nil
+}
+
+// Enabled returns whether the local cache is enabled by the log options cfg.
+func Enabled(cfg map[string]string) bool {
+	disabled, _ := strconv.ParseBool(cfg[cacheDisabledKey])
+	return !disabled
+}
+
+// MergeDefaultLogConfig adds the cache options of the daemon's default log
+// options to the log options of a container. Unlike the options of the
+// logging drivers, they apply whatever the logging driver of the container.
+func MergeDefaultLogConfig(cfg, defaults map[string]string) {
+	for k, v := range defaults {
+		if !builtInCacheLogOpts[k] {
+			continue
+		}
+		if _, ok := cfg[k]; !ok {
+			cfg[k] = v
+		}
+	}
+}
+
+// cacheInfo returns the info used to create the cache driver, with the cache
+// options as the options of the driver.
+func cacheInfo(info logger.Info) logger.Info {
+	cfg := make(map[string]string)
+	for k, v := range info.Config {
+		if k != cacheDisabledKey && builtInCacheLogOpts[k] {
+			cfg[k[len(cachePrefix):]] = v
+		}
+	}
+	info.Config = cfg
+	return info
+}
+
+func validateLogCacheOpts(cfg map[string]string) error {
+	if v, ok := cfg[cacheDisabledKey]; ok {
+		if _, err := strconv.ParseBool(v); err != nil {
+			return errors.Wrapf(err, "invalid value for option %s", cacheDisabledKey)
+		}
+	}
+	opts := make(map[string]string)
+	for k, v := range cfg {
+		if k != cacheDisabledKey && builtInCacheLogOpts[k] {
+			opts[k[len(cachePrefix):]] = v
+		}
+	}
+	if err := local.ValidateLogOpt(opts); err != nil {
+		return errors.Wrap(err, "invalid log cache options")
+	}
+	return nil
+}
+
+type loggerWithCache struct {
+	l     logger.Logger
+	cache logger.Logger
+}
+
+func (l *loggerWithCache) Log(msg *logger.Message) error {
+	// copy the message as the original will be reset once the call to `Log` is complete
+	dup := logger.NewMessage()
+	dumbCopyMessage(dup, msg)
+
+	if err := l.l.Log(msg); err != nil {
+		logger.PutMessage(dup)
+		return err
+	}
+	if err := l.cache.Log(dup); err != nil {
+		logrus.WithError(err).Warn("Error writing to local log cache")
+	}
+	return nil
+}
+
+func (l *loggerWithCache) Name() string {
+	return l.l.Name()
+}
+
+func (l *loggerWithCache) ReadLogs(config logger.ReadConfig) *logger.LogWatcher {
+	return l.cache.(logger.LogReader).ReadLogs(config)
+}
+
+func (l *loggerWithCache) Close() error {
+	err := l.l.Close()
+	if err := l.cache.Close(); err != nil {
+		logrus.WithError(err).Warn("Error while closing local log cache")
+	}
+	return err
+}
+
+// dumbCopyMessage is a bit of a fake copy but avoids extra allocations which
+// are not necessary for this use case.
+func dumbCopyMessage(dst, src *logger.Message) {
+	dst.Source = src.Source
+	dst.Timestamp = src.Timestamp
+	dst.PLogMetaData = src.PLogMetaData
+	dst.Err = src.Err
+	dst.Attrs = append([]backend.LogAttr(nil), src.Attrs...)
+	dst.Line = append(dst.Line[:0], src.Line...)
+}
@@ -0,0 +1,83 @@
+package cache // import "github.com/docker/docker/daemon/logger/loggerutils/cache"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/daemon/logger"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+type fakeLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *fakeLogger) Log(msg *logger.Message) error {
+	l.mu.Lock()
+	l.lines = append(l.lines, string(msg.Line))
+	l.mu.Unlock()
+	logger.PutMessage(msg)
+	return nil
+}
+
+func (l *fakeLogger) Name() string { return "fake" }
+
+func (l *fakeLogger) Close() error { return nil }
+
+func TestWithLocalCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "log-cache")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	driver := &fakeLogger{}
+	l, err := WithLocalCache(driver, logger.Info{
+		LogPath: filepath.Join(dir, "container-cached.log"),
+		Config:  map[string]string{"cache-max-file": "2", "mode": "blocking"},
+	})
+	assert.NilError(t, err)
+	defer l.Close()
+	assert.Check(t, is.Equal(l.Name(), "fake"))
+
+	for _, line := range []string{"one", "two", "three"} {
+		msg := logger.NewMessage()
+		msg.Source = "stdout"
+		msg.Timestamp = time.Now()
+		msg.Line = append(msg.Line, line...)
+		assert.NilError(t, l.Log(msg))
+	}
+	assert.Check(t, is.DeepEqual(driver.lines, []string{"one", "two", "three"}))
+
+	lw := l.(logger.LogReader).ReadLogs(logger.ReadConfig{Tail: 2})
+	defer lw.ConsumerGone()
+	for _, line := range []string{"two\n", "three\n"} {
+		select {
+		case msg := <-lw.Msg:
+			assert.Check(t, is.Equal(string(msg.Line), line))
+		case err := <-lw.Err:
+			t.Fatal(err)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timeout waiting for cached log message")
+		}
+	}
+}
+
+func TestValidateLogCacheOpts(t *testing.T) {
+	assert.Check(t, validateLogCacheOpts(map[string]string{"cache-disabled": "true", "cache-max-size": "10m", "tag": "foo"}))
+	assert.Check(t, is.ErrorContains(validateLogCacheOpts(map[string]string{"cache-disabled": "maybe"}), "cache-disabled"))
+	assert.Check(t, is.ErrorContains(logger.ValidateLogOpts("local", map[string]string{"cache-foo": "bar"}), "unknown log opt"))
+	assert.Check(t, logger.ValidateLogOpts("local", map[string]string{"cache-max-file": "3"}))
+}
+
+func TestMergeDefaultLogConfig(t *testing.T) {
+	cfg := map[string]string{"cache-max-file": "2"}
+	MergeDefaultLogConfig(cfg, map[string]string{"cache-max-file": "5", "cache-disabled": "true", "max-file": "10"})
+	assert.Check(t, is.DeepEqual(cfg, map[string]string{"cache-max-file": "2", "cache-disabled": "true"}))
+	assert.Check(t, !Enabled(cfg))
+	assert.Check(t, Enabled(map[string]string{}))
+}
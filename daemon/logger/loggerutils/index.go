@@ -0,0 +1,309 @@
+package loggerutils // import "github.com/docker/docker/daemon/logger/loggerutils"
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/pools"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Compression formats of the rotated log files.
+const (
+	CompressionNone = ""
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+const (
+	indexExt = ".idx"
+
+	// defaultIndexInterval is the number of bytes of log entries after which
+	// a new chunk is started in the index of a log file.
+	defaultIndexInterval = 1024 * 1024
+)
+
+// compressionExts are the extensions of the rotated log files by compression
+// format.
+var compressionExts = map[string]string{
+	CompressionNone: "",
+	CompressionGzip: ".gz",
+	CompressionZstd: ".zst",
+}
+
+// rotatedExts are the extensions of all the files making up a rotated log
+// file, whatever the compression format it was written with.
+var rotatedExts = []string{"", ".gz", ".zst", indexExt}
+
+// fileIndex is the time/offset index of a log file. It is kept in memory
+// while the file is written, and stored next to the file when it is rotated
+// so that reading can start close to the requested log entries.
+type fileIndex struct {
+	LastTime time.Time    `json:"lastTime,omitempty"`
+	Chunks   []indexChunk `json:"chunks"`
+}
+
+// indexChunk is a range of consecutive entries of a log file. Each chunk of a
+// compressed file is compressed separately, so decompression can start at any
+// chunk.
+type indexChunk struct {
+	// Time is the timestamp of the first entry, or zero if it's unknown.
+	Time time.Time `json:"time"`
+	// Entries is the number of entries in the chunk.
+	Entries int `json:"entries"`
+	// Offset is the offset of the first entry in the uncompressed file.
+	Offset int64 `json:"offset"`
+	// CompressedOffset is the offset of the chunk in the compressed file.
+	CompressedOffset int64 `json:"compressedOffset,omitempty"`
+}
+
+// add records an entry with timestamp t written at offset, starting a new
+// chunk if the current one is at least interval bytes long.
+func (idx *fileIndex) add(t time.Time, offset, interval int64) {
+	if n := len(idx.Chunks); n == 0 || offset-idx.Chunks[n-1].Offset >= interval {
+		idx.Chunks = append(idx.Chunks, indexChunk{Time: t, Offset: offset})
+	}
+	idx.Chunks[len(idx.Chunks)-1].Entries++
+	idx.LastTime = t
+}
+
+// window returns the range of chunks [start, end) which hold the entries
+// requested by config, or false if none of the chunks do. tail is the number
+// of lines read from the end of the log files, or 0 if the files are read
+// entirely.
+//
+// When tailing, the tail is taken from all the entries and only then
+// filtered by config.Until, so the end of the file is always kept.
+func (idx *fileIndex) window(config logger.ReadConfig, tail int) (start, end int, ok bool) {
+	chunks := idx.Chunks
+	end = len(chunks)
+	if end == 0 {
+		return 0, 0, false
+	}
+	if !config.Since.IsZero() {
+		if !idx.LastTime.IsZero() && idx.LastTime.Before(config.Since) {
+			return 0, 0, false
+		}
+		for i := 1; i < len(chunks) && !chunks[i].Time.IsZero() && chunks[i].Time.Before(config.Since); i++ {
+			start = i
+		}
+	}
+	if tail > 0 {
+		var n int
+		for i := len(chunks) - 1; i > start; i-- {
+			if n += chunks[i].Entries; n >= tail {
+				start = i
+				break
+			}
+		}
+		return start, end, true
+	}
+	if !config.Until.IsZero() {
+		for i, c := range chunks {
+			if !c.Time.IsZero() && c.Time.After(config.Until) {
+				end = i
+				break
+			}
+		}
+	}
+	return start, end, start < end
+}
+
+// bounds returns the offsets of the chunks [start, end) in a file of the
+// given size. compressed selects the offsets in the compressed file.
+func (idx *fileIndex) bounds(start, end int, size int64, compressed bool) (int64, int64) {
+	offset := func(c indexChunk) int64 {
+		if compressed {
+			return c.CompressedOffset
+		}
+		return c.Offset
+	}
+	from, to := offset(idx.Chunks[start]), size
+	if end < len(idx.Chunks) {
+		to = offset(idx.Chunks[end])
+	}
+	return from, to
+}
+
+func readIndex(path string) *fileIndex {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logrus.WithError(err).WithField("file", path).Warn("Failed to read log file index")
+		}
+		return nil
+	}
+	idx := &fileIndex{}
+	if err := json.Unmarshal(b, idx); err != nil || len(idx.Chunks) == 0 {
+		logrus.WithError(err).WithField("file", path).Warn("Ignoring invalid log file index")
+		return nil
+	}
+	return idx
+}
+
+func writeIndex(path string, idx *fileIndex) error {
+	b, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(path, b, 0640)
+}
+
+// compressFile compresses the log file fileName chunk by chunk, as described
+// by its index, and stores the index alongside the compressed file.
+func compressFile(fileName, compression string, idx *fileIndex) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		logrus.Errorf("Failed to open log file: %v", err)
+		return
+	}
+	defer func() {
+		file.Close()
+		err := os.Remove(fileName)
+		if err != nil {
+			logrus.Errorf("Failed to remove source log file: %v", err)
+		}
+	}()
+
+	stat, err := file.Stat()
+	if err != nil {
+		logrus.Errorf("Failed to stat log file: %v", err)
+		return
+	}
+	if len(idx.Chunks) == 0 {
+		idx.Chunks = []indexChunk{{}}
+	}
+
+	compressedName := fileName + compressionExts[compression]
+	outFile, err := os.OpenFile(compressedName, os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0640)
+	if err != nil {
+		logrus.Errorf("Failed to open or create compressed log file: %v", err)
+		return
+	}
+	defer func() {
+		outFile.Close()
+		if err != nil {
+			os.Remove(compressedName)
+		}
+	}()
+
+	var (
+		offset int64
+		zw     *zstd.Encoder
+	)
+	for i := range idx.Chunks {
+		idx.Chunks[i].CompressedOffset = offset
+
+		var cw io.WriteCloser
+		switch compression {
+		case CompressionZstd:
+			if zw == nil {
+				zw, err = zstd.NewWriter(outFile, zstd.WithEncoderConcurrency(1))
+				if err != nil {
+					logrus.WithError(err).Error("Failed to create zstd encoder")
+					return
+				}
+			} else {
+				zw.Reset(outFile)
+			}
+			cw = zw
+		default:
+			gw := gzip.NewWriter(outFile)
+			if i == 0 {
+				// Add the last log entry timestamp to the gzip header
+				gw.Header.Extra, err = json.Marshal(&rotateFileMetadata{LastTime: idx.LastTime})
+				if err != nil {
+					// Here log the error only and don't return since this is just an optimization.
+					logrus.Warningf("Failed to marshal gzip header as JSON: %v", err)
+				}
+			}
+			cw = gw
+		}
+
+		from, to := idx.bounds(i, i+1, stat.Size(), false)
+		if _, err = pools.Copy(cw, io.NewSectionReader(file, from, to-from)); err == nil {
+			err = cw.Close()
+		}
+		if err != nil {
+			logrus.WithError(err).WithField("module", "container.logs").WithField("file", fileName).Error("Error compressing log file")
+			return
+		}
+		if offset, err = outFile.Seek(0, io.SeekCurrent); err != nil {
+			logrus.WithError(err).WithField("file", compressedName).Error("Error getting size of compressed log file")
+			return
+		}
+	}
+
+	if err := writeIndex(fileName+indexExt, idx); err != nil {
+		logrus.WithError(err).WithField("file", fileName).Warn("Failed to write log file index")
+	}
+}
+
+func newDecompressor(compression string, r io.Reader) (io.ReadCloser, error) {
+	switch compression {
+	case CompressionZstd:
+		zr, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, errors.Wrap(err, "error making zstd reader for compressed log file")
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "error making gzip reader for compressed log file")
+		}
+		return gr, nil
+	}
+}
+
+// decompressRange decompresses the chunks of fileName between the compressed
+// offsets from and to into destFileName.
+func decompressRange(fileName, compression, destFileName string, from, to int64) (*os.File, error) {
+	cf, err := os.Open(fileName)
+	if err != nil {
+		return nil, errors.Wrap(err, "error opening file for decompression")
+	}
+	defer cf.Close()
+
+	rc, err := newDecompressor(compression, io.NewSectionReader(cf, from, to-from))
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return copyDecompressed(rc, destFileName)
+}
+
+func copyDecompressed(rc io.Reader, destFileName string) (*os.File, error) {
+	rs, err := os.OpenFile(destFileName, os.O_CREATE|os.O_RDWR, 0640)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating file for copying decompressed log stream")
+	}
+
+	_, err = pools.Copy(rs, rc)
+	if err != nil {
+		rs.Close()
+		rErr := os.Remove(rs.Name())
+		if rErr != nil && !os.IsNotExist(rErr) {
+			logrus.Errorf("Failed to remove logfile: %v", rErr)
+		}
+		return nil, errors.Wrap(err, "error while copying decompressed log stream to file")
+	}
+
+	return rs, nil
+}
+
+// rangeFileName returns the name of the file the chunks of fileName between
+// the offsets from and to are decompressed to.
+func rangeFileName(fileName string, from, to int64) string {
+	return fmt.Sprintf("%s.%d-%d%s", fileName, from, to, tmpLogfileSuffix)
+}
@@ -0,0 +1,166 @@
+package loggerutils
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/pkg/tailfile"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestFileIndexWindow(t *testing.T) {
+	base := time.Now()
+	at := func(sec int) time.Time { return base.Add(time.Duration(sec) * time.Second) }
+	index := &fileIndex{
+		LastTime: at(39),
+		Chunks: []indexChunk{
+			{Time: at(0), Entries: 10, Offset: 0},
+			{Time: at(10), Entries: 10, Offset: 100},
+			{Time: at(20), Entries: 10, Offset: 200},
+			{Time: at(30), Entries: 10, Offset: 300},
+		},
+	}
+
+	for _, tc := range []struct {
+		name       string
+		config     logger.ReadConfig
+		tail       int
+		start, end int
+		ok         bool
+	}{
+		{name: "all", start: 0, end: 4, ok: true},
+		{name: "since", config: logger.ReadConfig{Since: at(25)}, start: 2, end: 4, ok: true},
+		{name: "since chunk start", config: logger.ReadConfig{Since: at(20)}, start: 1, end: 4, ok: true},
+		{name: "since after last", config: logger.ReadConfig{Since: at(40)}},
+		{name: "until", config: logger.ReadConfig{Until: at(15)}, start: 0, end: 2, ok: true},
+		{name: "until before first", config: logger.ReadConfig{Until: base.Add(-time.Second)}},
+		{name: "since and until", config: logger.ReadConfig{Since: at(12), Until: at(25)}, start: 1, end: 3, ok: true},
+		{name: "tail", tail: 15, start: 2, end: 4, ok: true},
+		{name: "tail more than available", tail: 100, start: 0, end: 4, ok: true},
+		{name: "tail ignores until", config: logger.ReadConfig{Until: at(15)}, tail: 5, start: 3, end: 4, ok: true},
+		{name: "tail and since", config: logger.ReadConfig{Since: at(35)}, tail: 25, start: 3, end: 4, ok: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end, ok := index.window(tc.config, tc.tail)
+			assert.Check(t, is.Equal(ok, tc.ok))
+			if tc.ok {
+				assert.Check(t, is.Equal(start, tc.start))
+				assert.Check(t, is.Equal(end, tc.end))
+			}
+		})
+	}
+
+	// the entries written before the daemon restarted are not indexed
+	unknown := &fileIndex{Chunks: append([]indexChunk{{Offset: 0}}, index.Chunks[1:]...)}
+	start, end, ok := unknown.window(logger.ReadConfig{Since: at(5)}, 0)
+	assert.Check(t, ok)
+	assert.Check(t, is.Equal(start, 0))
+	assert.Check(t, is.Equal(end, 4))
+}
+
+func TestReadLogsIndexed(t *testing.T) {
+	marshal := func(msg *logger.Message) ([]byte, error) {
+		return []byte(fmt.Sprintf("%d %s\n", msg.Timestamp.UnixNano(), msg.Line)), nil
+	}
+	createDecoder := func(r io.Reader) func() (*logger.Message, error) {
+		scanner := bufio.NewScanner(r)
+		return func() (*logger.Message, error) {
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return nil, err
+				}
+				return nil, io.EOF
+			}
+			fields := strings.SplitN(scanner.Text(), " ", 2)
+			ts, err := strconv.ParseInt(fields[0], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return &logger.Message{Line: []byte(fields[1]), Timestamp: time.Unix(0, ts)}, nil
+		}
+	}
+	tailReader := func(ctx context.Context, r SizeReaderAt, lines int) (io.Reader, int, error) {
+		return tailfile.NewTailReader(ctx, r, lines)
+	}
+
+	for _, compression := range []string{CompressionNone, CompressionGzip, CompressionZstd} {
+		t.Run("compression="+compression, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "logfile-index")
+			assert.NilError(t, err)
+			defer os.RemoveAll(dir)
+
+			lf, err := NewLogFile(filepath.Join(dir, "container.log"), 300, 5, compression, marshal, createDecoder, 0640, tailReader)
+			assert.NilError(t, err)
+			defer lf.Close()
+			lf.indexInterval = 50
+
+			base := time.Now()
+			at := func(sec int) time.Time { return base.Add(time.Duration(sec) * time.Second) }
+			for i := 0; i < 40; i++ {
+				msg := logger.NewMessage()
+				msg.Line = append(msg.Line, fmt.Sprintf("message %02d", i)...)
+				msg.Timestamp = at(i)
+				assert.NilError(t, lf.WriteLogEntry(msg))
+			}
+
+			read := func(config logger.ReadConfig) []string {
+				watcher := logger.NewLogWatcher()
+				done := make(chan struct{})
+				go func() {
+					lf.ReadLogs(config, watcher)
+					close(done)
+				}()
+				var lines []string
+				for {
+					select {
+					case msg := <-watcher.Msg:
+						lines = append(lines, string(msg.Line))
+					case err := <-watcher.Err:
+						t.Fatal(err)
+					case <-done:
+						// drain the messages buffered by the watcher
+						for {
+							select {
+							case msg := <-watcher.Msg:
+								lines = append(lines, string(msg.Line))
+							default:
+								return lines
+							}
+						}
+					}
+				}
+			}
+
+			lines := read(logger.ReadConfig{Tail: -1, Since: at(12), Until: at(15)})
+			assert.Check(t, is.DeepEqual(lines, []string{"message 12", "message 13", "message 14", "message 15"}))
+
+			lines = read(logger.ReadConfig{Tail: 3})
+			assert.Check(t, is.DeepEqual(lines, []string{"message 37", "message 38", "message 39"}))
+
+			lines = read(logger.ReadConfig{Tail: -1})
+			assert.Check(t, is.Len(lines, 40))
+
+			for i := 1; i <= 3; i++ {
+				_, err := os.Stat(filepath.Join(dir, fmt.Sprintf("container.log.%d%s", i, indexExt)))
+				assert.Check(t, err)
+				_, err = os.Stat(filepath.Join(dir, fmt.Sprintf("container.log.%d%s", i, compressionExts[compression])))
+				assert.Check(t, err)
+			}
+			// the decompressed copies are removed once read
+			tmpFiles, err := filepath.Glob(filepath.Join(dir, "*"+tmpLogfileSuffix))
+			assert.NilError(t, err)
+			assert.Check(t, is.Len(tmpFiles, 0))
+		})
+	}
+}
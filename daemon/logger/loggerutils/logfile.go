@@ -15,7 +15,6 @@ import (
 
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/pkg/filenotify"
-	"github.com/docker/docker/pkg/pools"
 	"github.com/docker/docker/pkg/pubsub"
 	"github.com/fsnotify/fsnotify"
 	"github.com/pkg/errors"
@@ -84,8 +83,10 @@ type LogFile struct {
 	capacity        int64      // maximum size of each file
 	currentSize     int64      // current size of the latest file
 	maxFiles        int        // maximum number of files
-	compress        bool       // whether old versions of log files are compressed
+	compression     string     // compression format of old versions of log files
 	lastTimestamp   time.Time  // timestamp of the last log
+	index           *fileIndex // index of the latest file
+	indexInterval   int64      // number of bytes between two chunks of the index
 	filesRefCounter refCounter // keep reference-counted of decompressed files
 	notifyRotate    *pubsub.Publisher
 	marshal         logger.MarshalFunc
@@ -109,8 +110,9 @@ type SizeReaderAt interface {
 // contains, and any error that occurs.
 type GetTailReaderFunc func(ctx context.Context, f SizeReaderAt, nLogLines int) (rdr io.Reader, nLines int, err error)
 
-// NewLogFile creates new LogFile
-func NewLogFile(logPath string, capacity int64, maxFiles int, compress bool, marshaller logger.MarshalFunc, decodeFunc makeDecoderFunc, perms os.FileMode, getTailReader GetTailReaderFunc) (*LogFile, error) {
+// NewLogFile creates new LogFile. compression is the format old versions of
+// the log file are compressed with, CompressionNone to leave them uncompressed.
+func NewLogFile(logPath string, capacity int64, maxFiles int, compression string, marshaller logger.MarshalFunc, decodeFunc makeDecoderFunc, perms os.FileMode, getTailReader GetTailReaderFunc) (*LogFile, error) {
 	log, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, perms)
 	if err != nil {
 		return nil, err
@@ -121,12 +123,21 @@ func NewLogFile(logPath string, capacity int64, maxFiles int, compress bool, mar
 		return nil, err
 	}
 
+	index := &fileIndex{}
+	if size > 0 {
+		// The entries written before are not indexed, so start with a chunk
+		// covering them which can't be skipped.
+		index.Chunks = append(index.Chunks, indexChunk{})
+	}
+
 	return &LogFile{
 		f:               log,
 		capacity:        capacity,
 		currentSize:     size,
 		maxFiles:        maxFiles,
-		compress:        compress,
+		compression:     compression,
+		index:           index,
+		indexInterval:   defaultIndexInterval,
 		filesRefCounter: refCounter{counter: make(map[string]int)},
 		notifyRotate:    pubsub.NewPublisher(0, 1),
 		marshal:         marshaller,
@@ -144,6 +155,7 @@ func (w *LogFile) WriteLogEntry(msg *logger.Message) error {
 		return errors.Wrap(err, "error marshalling log message")
 	}
 
+	ts := msg.Timestamp
 	logger.PutMessage(msg)
 
 	w.mu.Lock()
@@ -159,8 +171,9 @@ func (w *LogFile) WriteLogEntry(msg *logger.Message) error {
 
 	n, err := w.f.Write(b)
 	if err == nil {
+		w.index.add(ts, w.currentSize, w.indexInterval)
 		w.currentSize += int64(n)
-		w.lastTimestamp = msg.Timestamp
+		w.lastTimestamp = ts
 	}
 	w.mu.Unlock()
 	return err
@@ -178,7 +191,7 @@ func (w *LogFile) checkCapacityAndRotate() error {
 			w.rotateMu.Unlock()
 			return errors.Wrap(err, "error closing file")
 		}
-		if err := rotate(fname, w.maxFiles, w.compress); err != nil {
+		if err := rotate(fname, w.maxFiles); err != nil {
 			w.rotateMu.Unlock()
 			return err
 		}
@@ -189,15 +202,25 @@ func (w *LogFile) checkCapacityAndRotate() error {
 		}
 		w.f = file
 		w.currentSize = 0
+		index := w.index
+		w.index = &fileIndex{}
 		w.notifyRotate.Publish(struct{}{})
 
-		if w.maxFiles <= 1 || !w.compress {
+		if w.maxFiles <= 1 {
+			w.rotateMu.Unlock()
+			return nil
+		}
+
+		if w.compression == CompressionNone {
+			if err := writeIndex(fname+".1"+indexExt, index); err != nil {
+				logrus.WithError(err).WithField("file", fname+".1").Warn("Failed to write log file index")
+			}
 			w.rotateMu.Unlock()
 			return nil
 		}
 
 		go func() {
-			compressFile(fname+".1", w.lastTimestamp)
+			compressFile(fname+".1", w.compression, index)
 			w.rotateMu.Unlock()
 		}()
 	}
@@ -205,27 +228,29 @@ func (w *LogFile) checkCapacityAndRotate() error {
 	return nil
 }
 
-func rotate(name string, maxFiles int, compress bool) error {
+// rotate shifts the old versions of the log file, along with their index,
+// dropping the oldest one. Files are shifted whatever their compression
+// format, so that rotation keeps working when the format is changed.
+func rotate(name string, maxFiles int) error {
 	if maxFiles < 2 {
 		return nil
 	}
 
-	var extension string
-	if compress {
-		extension = ".gz"
-	}
-
-	lastFile := fmt.Sprintf("%s.%d%s", name, maxFiles-1, extension)
-	err := os.Remove(lastFile)
-	if err != nil && !os.IsNotExist(err) {
-		return errors.Wrap(err, "error removing oldest log file")
+	for _, extension := range rotatedExts {
+		lastFile := fmt.Sprintf("%s.%d%s", name, maxFiles-1, extension)
+		err := os.Remove(lastFile)
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "error removing oldest log file")
+		}
 	}
 
 	for i := maxFiles - 1; i > 1; i-- {
-		toPath := name + "." + strconv.Itoa(i) + extension
-		fromPath := name + "." + strconv.Itoa(i-1) + extension
-		if err := os.Rename(fromPath, toPath); err != nil && !os.IsNotExist(err) {
-			return err
+		for _, extension := range rotatedExts {
+			toPath := name + "." + strconv.Itoa(i) + extension
+			fromPath := name + "." + strconv.Itoa(i-1) + extension
+			if err := os.Rename(fromPath, toPath); err != nil && !os.IsNotExist(err) {
+				return err
+			}
 		}
 	}
 
@@ -236,51 +261,6 @@ func rotate(name string, maxFiles int, compress bool) error {
 	return nil
 }
 
-func compressFile(fileName string, lastTimestamp time.Time) {
-	file, err := os.Open(fileName)
-	if err != nil {
-		logrus.Errorf("Failed to open log file: %v", err)
-		return
-	}
-	defer func() {
-		file.Close()
-		err := os.Remove(fileName)
-		if err != nil {
-			logrus.Errorf("Failed to remove source log file: %v", err)
-		}
-	}()
-
-	outFile, err := os.OpenFile(fileName+".gz", os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0640)
-	if err != nil {
-		logrus.Errorf("Failed to open or create gzip log file: %v", err)
-		return
-	}
-	defer func() {
-		outFile.Close()
-		if err != nil {
-			os.Remove(fileName + ".gz")
-		}
-	}()
-
-	compressWriter := gzip.NewWriter(outFile)
-	defer compressWriter.Close()
-
-	// Add the last log entry timestramp to the gzip header
-	extra := rotateFileMetadata{}
-	extra.LastTime = lastTimestamp
-	compressWriter.Header.Extra, err = json.Marshal(&extra)
-	if err != nil {
-		// Here log the error only and don't return since this is just an optimization.
-		logrus.Warningf("Failed to marshal gzip header as JSON: %v", err)
-	}
-
-	_, err = pools.Copy(compressWriter, file)
-	if err != nil {
-		logrus.WithError(err).WithField("module", "container.logs").WithField("file", fileName).Error("Error compressing log file")
-		return
-	}
-}
-
 // MaxFiles return maximum number of files
 func (w *LogFile) MaxFiles() int {
 	return w.maxFiles
@@ -305,6 +285,12 @@ func (w *LogFile) Close() error {
 // Note: Using the follow option can become inconsistent in cases with very frequent rotations and max log files is 1.
 // TODO: Consider a different implementation which can effectively follow logs under frequent rotations.
 func (w *LogFile) ReadLogs(config logger.ReadConfig, watcher *logger.LogWatcher) {
+	filter, err := logger.NewMessageFilter(config)
+	if err != nil {
+		watcher.Err <- err
+		return
+	}
+
 	w.mu.RLock()
 	currentFile, err := os.Open(w.f.Name())
 	if err != nil {
@@ -325,7 +311,12 @@ func (w *LogFile) ReadLogs(config logger.ReadConfig, watcher *logger.LogWatcher)
 		// TODO(@cpuguy83): Instead of opening every file, only get the files which
 		// are needed to tail.
 		// This is especially costly when compression is enabled.
-		files, err := w.openRotatedFiles(config)
+		tail := config.Tail
+		if filter != nil {
+			// tailing with filters reads the files entirely
+			tail = -1
+		}
+		files, err := w.openRotatedFiles(config, tail)
 		w.mu.RUnlock()
 		if err != nil {
 			watcher.Err <- err
@@ -347,19 +338,13 @@ func (w *LogFile) ReadLogs(config logger.ReadConfig, watcher *logger.LogWatcher)
 
 		readers := make([]SizeReaderAt, 0, len(files)+1)
 		for _, f := range files {
-			stat, err := f.Stat()
-			if err != nil {
-				watcher.Err <- errors.Wrap(err, "error reading size of rotated file")
-				closeFiles()
-				return
-			}
-			readers = append(readers, io.NewSectionReader(f, 0, stat.Size()))
+			readers = append(readers, io.NewSectionReader(f, f.offset, f.size))
 		}
 		if currentChunk.Size() > 0 {
 			readers = append(readers, currentChunk)
 		}
 
-		tailFiles(readers, watcher, w.createDecoder, w.getTailReader, config)
+		tailFiles(readers, watcher, w.createDecoder, w.getTailReader, config, filter)
 		closeFiles()
 
 		w.mu.RLock()
@@ -373,10 +358,22 @@ func (w *LogFile) ReadLogs(config logger.ReadConfig, watcher *logger.LogWatcher)
 
 	notifyRotate := w.notifyRotate.Subscribe()
 	defer w.notifyRotate.Evict(notifyRotate)
-	followLogs(currentFile, watcher, notifyRotate, w.createDecoder, config.Since, config.Until)
+	followLogs(currentFile, watcher, notifyRotate, w.createDecoder, config.Since, config.Until, filter)
 }
 
-func (w *LogFile) openRotatedFiles(config logger.ReadConfig) (files []*os.File, err error) {
+// rotatedFile is the part of a rotated log file which has to be read.
+type rotatedFile struct {
+	*os.File
+	offset int64
+	size   int64
+}
+
+// openRotatedFiles opens the rotated log files, oldest first. Only the part
+// of the files that holds the entries requested by config is opened when the
+// files are indexed, decompressing only that part of compressed files. tail
+// is the number of lines that will be read from the end of the files, or a
+// negative value if they are read entirely.
+func (w *LogFile) openRotatedFiles(config logger.ReadConfig, tail int) (files []*rotatedFile, err error) {
 	w.rotateMu.Lock()
 	defer w.rotateMu.Unlock()
 
@@ -387,8 +384,8 @@ func (w *LogFile) openRotatedFiles(config logger.ReadConfig) (files []*os.File,
 		for _, f := range files {
 			f.Close()
 			if strings.HasSuffix(f.Name(), tmpLogfileSuffix) {
-				err := os.Remove(f.Name())
-				if err != nil && !os.IsNotExist(err) {
+				err := w.filesRefCounter.Dereference(f.Name())
+				if err != nil {
 					logrus.Warnf("Failed to remove logfile: %v", err)
 				}
 			}
@@ -396,21 +393,41 @@ func (w *LogFile) openRotatedFiles(config logger.ReadConfig) (files []*os.File,
 	}()
 
 	for i := w.maxFiles; i > 1; i-- {
-		f, err := os.Open(fmt.Sprintf("%s.%d", w.f.Name(), i-1))
-		if err != nil {
-			if !os.IsNotExist(err) {
-				return nil, errors.Wrap(err, "error opening rotated log file")
+		name := fmt.Sprintf("%s.%d", w.f.Name(), i-1)
+
+		var start, end int
+		index := readIndex(name + indexExt)
+		if index != nil {
+			var ok bool
+			start, end, ok = index.window(config, tail)
+			if !ok {
+				// none of the requested entries are in this file
+				continue
 			}
+		}
 
-			fileName := fmt.Sprintf("%s.%d.gz", w.f.Name(), i-1)
-			decompressedFileName := fileName + tmpLogfileSuffix
-			tmpFile, err := w.filesRefCounter.GetReference(decompressedFileName, func(refFileName string, exists bool) (*os.File, error) {
-				if exists {
-					return os.Open(refFileName)
-				}
-				return decompressfile(fileName, refFileName, config.Since)
-			})
+		f, err := os.Open(name)
+		if err == nil {
+			stat, err := f.Stat()
+			if err != nil {
+				f.Close()
+				return nil, errors.Wrap(err, "error reading size of rotated file")
+			}
+			rf := &rotatedFile{File: f, size: stat.Size()}
+			if index != nil {
+				from, to := index.bounds(start, end, stat.Size(), false)
+				rf.offset, rf.size = from, to-from
+			}
+			files = append(files, rf)
+			continue
+		}
+		if !os.IsNotExist(err) {
+			return nil, errors.Wrap(err, "error opening rotated log file")
+		}
 
+		for _, compression := range []string{CompressionGzip, CompressionZstd} {
+			fileName := name + compressionExts[compression]
+			tmpFile, err := w.openCompressedFile(fileName, compression, index, start, end, config.Since)
 			if err != nil {
 				if !os.IsNotExist(errors.Cause(err)) {
 					return nil, errors.Wrap(err, "error getting reference to decompressed log file")
@@ -421,52 +438,70 @@ func (w *LogFile) openRotatedFiles(config logger.ReadConfig) (files []*os.File,
 				// The log before `config.Since` does not need to read
 				break
 			}
-
-			files = append(files, tmpFile)
-			continue
+			stat, err := tmpFile.Stat()
+			if err != nil {
+				tmpFile.Close()
+				w.filesRefCounter.Dereference(tmpFile.Name())
+				return nil, errors.Wrap(err, "error reading size of rotated file")
+			}
+			files = append(files, &rotatedFile{File: tmpFile, size: stat.Size()})
+			break
 		}
-		files = append(files, f)
 	}
 
 	return files, nil
 }
 
-func decompressfile(fileName, destFileName string, since time.Time) (*os.File, error) {
-	cf, err := os.Open(fileName)
-	if err != nil {
-		return nil, errors.Wrap(err, "error opening file for decompression")
+// openCompressedFile returns a reference to a decompressed copy of the
+// compressed log file fileName. If the file is indexed, only the chunks
+// [start, end) are decompressed.
+func (w *LogFile) openCompressedFile(fileName, compression string, index *fileIndex, start, end int, since time.Time) (*os.File, error) {
+	if index == nil {
+		decompressedFileName := fileName + tmpLogfileSuffix
+		return w.filesRefCounter.GetReference(decompressedFileName, func(refFileName string, exists bool) (*os.File, error) {
+			if exists {
+				return os.Open(refFileName)
+			}
+			return decompressfile(fileName, compression, refFileName, since)
+		})
 	}
-	defer cf.Close()
 
-	rc, err := gzip.NewReader(cf)
+	stat, err := os.Stat(fileName)
 	if err != nil {
-		return nil, errors.Wrap(err, "error making gzip reader for compressed log file")
+		return nil, err
 	}
-	defer rc.Close()
+	from, to := index.bounds(start, end, stat.Size(), true)
+	return w.filesRefCounter.GetReference(rangeFileName(fileName, from, to), func(refFileName string, exists bool) (*os.File, error) {
+		if exists {
+			return os.Open(refFileName)
+		}
+		return decompressRange(fileName, compression, refFileName, from, to)
+	})
+}
 
-	// Extract the last log entry timestramp from the gzip header
-	extra := &rotateFileMetadata{}
-	err = json.Unmarshal(rc.Header.Extra, extra)
-	if err == nil && extra.LastTime.Before(since) {
-		return nil, nil
+func decompressfile(fileName, compression, destFileName string, since time.Time) (*os.File, error) {
+	cf, err := os.Open(fileName)
+	if err != nil {
+		return nil, errors.Wrap(err, "error opening file for decompression")
 	}
+	defer cf.Close()
 
-	rs, err := os.OpenFile(destFileName, os.O_CREATE|os.O_RDWR, 0640)
+	rc, err := newDecompressor(compression, cf)
 	if err != nil {
-		return nil, errors.Wrap(err, "error creating file for copying decompressed log stream")
+		return nil, err
 	}
+	defer rc.Close()
 
-	_, err = pools.Copy(rs, rc)
-	if err != nil {
-		rs.Close()
-		rErr := os.Remove(rs.Name())
-		if rErr != nil && !os.IsNotExist(rErr) {
-			logrus.Errorf("Failed to remove logfile: %v", rErr)
+	if gr, ok := rc.(*gzip.Reader); ok {
+		// Extract the last log entry timestramp from the gzip header
+		extra := &rotateFileMetadata{}
+		err = json.Unmarshal(gr.Header.Extra, extra)
+		if err == nil && extra.LastTime.Before(since) {
+			return nil, nil
 		}
-		return nil, errors.Wrap(err, "error while copying decompressed log stream to file")
 	}
 
-	return rs, nil
+	return copyDecompressed(rc, destFileName)
 }
 
 func newSectionReader(f *os.File) (*io.SectionReader, error) {
@@ -479,7 +514,7 @@ func newSectionReader(f *os.File) (*io.SectionReader, error) {
 	return io.NewSectionReader(f, 0, size), nil
 }
 
-func tailFiles(files []SizeReaderAt, watcher *logger.LogWatcher, createDecoder makeDecoderFunc, getTailReader GetTailReaderFunc, config logger.ReadConfig) {
+func tailFiles(files []SizeReaderAt, watcher *logger.LogWatcher, createDecoder makeDecoderFunc, getTailReader GetTailReaderFunc, config logger.ReadConfig, filter *logger.MessageFilter) {
 	nLines := config.Tail
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -495,7 +530,10 @@ func tailFiles(files []SizeReaderAt, watcher *logger.LogWatcher, createDecoder m
 
 	readers := make([]io.Reader, 0, len(files))
 
-	if config.Tail > 0 {
+	// The tail reader counts lines regardless of the filter, so the last
+	// matching messages are found by reading all files instead.
+	filterTail := config.Tail > 0 && filter != nil
+	if config.Tail > 0 && !filterTail {
 		for i := len(files) - 1; i >= 0 && nLines > 0; i-- {
 			tail, n, err := getTailReader(ctx, files[i], nLines)
 			if err != nil {
@@ -511,6 +549,22 @@ func tailFiles(files []SizeReaderAt, watcher *logger.LogWatcher, createDecoder m
 		}
 	}
 
+	send := func(msg *logger.Message) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case watcher.Msg <- msg:
+			return true
+		}
+	}
+
+	// tail holds the last config.Tail matching messages when filtering,
+	// starting at index next once it is full.
+	var (
+		tail []*logger.Message
+		next int
+	)
+
 	rdr := io.MultiReader(readers...)
 	decodeLogLine := createDecoder(rdr)
 	for {
@@ -518,24 +572,41 @@ func tailFiles(files []SizeReaderAt, watcher *logger.LogWatcher, createDecoder m
 		if err != nil {
 			if errors.Cause(err) != io.EOF {
 				watcher.Err <- err
+				return
 			}
-			return
+			break
 		}
 		if !config.Since.IsZero() && msg.Timestamp.Before(config.Since) {
 			continue
 		}
 		if !config.Until.IsZero() && msg.Timestamp.After(config.Until) {
+			break
+		}
+		if !filter.Match(msg) {
+			continue
+		}
+		if filterTail {
+			if len(tail) < config.Tail {
+				tail = append(tail, msg)
+			} else {
+				tail[next] = msg
+				next = (next + 1) % config.Tail
+			}
+			continue
+		}
+		if !send(msg) {
 			return
 		}
-		select {
-		case <-ctx.Done():
+	}
+
+	for _, msg := range append(tail[next:], tail[:next]...) {
+		if !send(msg) {
 			return
-		case watcher.Msg <- msg:
 		}
 	}
 }
 
-func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, createDecoder makeDecoderFunc, since, until time.Time) {
+func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, createDecoder makeDecoderFunc, since, until time.Time, filter *logger.MessageFilter) {
 	decodeLogLine := createDecoder(f)
 
 	name := f.Name()
@@ -655,6 +726,9 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 		if !until.IsZero() && msg.Timestamp.After(until) {
 			return
 		}
+		if !filter.Match(msg) {
+			continue
+		}
 		// send the message, unless the consumer is gone
 		select {
 		case logWatcher.Msg <- msg:
@@ -8,6 +8,9 @@ var (
 	logWritesFailedCount metrics.Counter
 	logReadsFailedCount  metrics.Counter
 	totalPartialLogs     metrics.Counter
+
+	logMessagesSuppressedCount metrics.Counter
+	logBytesSuppressedCount    metrics.Counter
 )
 
 func init() {
@@ -17,5 +20,8 @@ func init() {
 	logReadsFailedCount = loggerMetrics.NewCounter("log_read_operations_failed", "Number of log reads from container stdio that failed")
 	totalPartialLogs = loggerMetrics.NewCounter("log_entries_size_greater_than_buffer", "Number of log entries which are larger than the log buffer")
 
+	logMessagesSuppressedCount = loggerMetrics.NewCounter("log_entries_suppressed", "Number of log entries dropped by the log rate limit")
+	logBytesSuppressedCount = loggerMetrics.NewCounter("log_entries_suppressed_bytes", "Number of bytes of the log entries dropped by the log rate limit")
+
 	metrics.Register(loggerMetrics)
 }
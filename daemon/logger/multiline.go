@@ -0,0 +1,174 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"regexp"
+	"sync"
+	"time"
+
+	types "github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/pkg/errors"
+)
+
+// Log options configuring the aggregation of multiline log entries by a
+// Copier.
+const (
+	multilinePatternOpt = "multiline-pattern"
+	multilineTimeoutOpt = "multiline-timeout"
+)
+
+const (
+	defaultMultilineTimeout = time.Second
+
+	// multilineMaxSize is the maximum size of an aggregated entry. Lines
+	// which would make an entry larger start a new one.
+	multilineMaxSize = 1024 * 1024
+)
+
+// MultilineConfig configures the aggregation of multiline log entries, such
+// as stack traces, into a single message.
+type MultilineConfig struct {
+	// Start matches the first line of an entry. The lines that don't match
+	// are appended to the current entry.
+	Start *regexp.Regexp
+	// Timeout is the time after which the current entry is logged if no
+	// line is copied.
+	Timeout time.Duration
+}
+
+// NewMultilineConfig creates a MultilineConfig from the log options of a
+// container. It returns nil if multiline aggregation is not configured.
+func NewMultilineConfig(cfg map[string]string) (*MultilineConfig, error) {
+	pattern, ok := cfg[multilinePatternOpt]
+	if !ok {
+		if _, ok := cfg[multilineTimeoutOpt]; ok {
+			return nil, errors.Errorf("%s requires %s to be set", multilineTimeoutOpt, multilinePatternOpt)
+		}
+		return nil, nil
+	}
+	start, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid value for %s", multilinePatternOpt)
+	}
+	c := &MultilineConfig{Start: start, Timeout: defaultMultilineTimeout}
+	if s, ok := cfg[multilineTimeoutOpt]; ok {
+		c.Timeout, err = time.ParseDuration(s)
+		if err != nil || c.Timeout <= 0 {
+			return nil, errors.Errorf("invalid value for %s: %s, must be a positive duration", multilineTimeoutOpt, s)
+		}
+	}
+	return c, nil
+}
+
+// validateMultilineOpts validates the multiline options in cfg.
+func validateMultilineOpts(cfg map[string]string) error {
+	_, err := NewMultilineConfig(cfg)
+	return err
+}
+
+// multilineAggregator merges the lines copied from a source into multiline
+// entries. An entry is written once the first line of the next one is
+// copied, or after the timeout. Entries larger than the buffer size of the
+// Logger are written as partial messages.
+type multilineAggregator struct {
+	cfg     *MultilineConfig
+	bufSize int
+	write   func(*Message)
+
+	mu      sync.Mutex
+	pending *Message
+	lastAdd time.Time
+	timer   *time.Timer
+	closed  bool
+}
+
+func newMultilineAggregator(cfg *MultilineConfig, bufSize int, write func(*Message)) *multilineAggregator {
+	return &multilineAggregator{cfg: cfg, bufSize: bufSize, write: write}
+}
+
+func (a *multilineAggregator) add(msg *Message) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if msg.PLogMetaData != nil {
+		// lines larger than the buffer are not aggregated
+		a.flush()
+		a.write(msg)
+		return
+	}
+
+	if a.pending != nil && !a.cfg.Start.Match(msg.Line) && len(a.pending.Line)+1+len(msg.Line) <= multilineMaxSize {
+		a.pending.Line = append(append(a.pending.Line, '\n'), msg.Line...)
+		PutMessage(msg)
+	} else {
+		a.flush()
+		a.pending = msg
+	}
+
+	a.lastAdd = time.Now()
+	if a.timer == nil {
+		a.timer = time.AfterFunc(a.cfg.Timeout, a.flushTimeout)
+	} else {
+		a.timer.Reset(a.cfg.Timeout)
+	}
+}
+
+func (a *multilineAggregator) flushTimeout() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed || a.pending == nil {
+		return
+	}
+	if d := a.cfg.Timeout - time.Since(a.lastAdd); d > 0 {
+		// a line was added in the meantime
+		a.timer.Reset(d)
+		return
+	}
+	a.flush()
+}
+
+// flush writes the pending entry, split into partial messages if it's larger
+// than the buffer size.
+func (a *multilineAggregator) flush() {
+	msg := a.pending
+	if msg == nil {
+		return
+	}
+	a.pending = nil
+
+	if len(msg.Line) <= a.bufSize {
+		a.write(msg)
+		return
+	}
+
+	totalPartialLogs.Inc(1)
+	id := stringid.GenerateRandomID()
+	line := msg.Line
+	for ordinal := 1; len(line) > 0; ordinal++ {
+		n := len(line)
+		if n > a.bufSize {
+			n = a.bufSize
+		}
+		part := NewMessage()
+		part.Source = msg.Source
+		part.Timestamp = msg.Timestamp
+		part.Line = append(part.Line, line[:n]...)
+		part.PLogMetaData = &types.PartialLogMetaData{ID: id, Ordinal: ordinal, Last: n == len(line)}
+		line = line[n:]
+		a.write(part)
+	}
+	PutMessage(msg)
+}
+
+// close writes the pending entry and stops the timeout.
+func (a *multilineAggregator) close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.closed = true
+	if a.timer != nil {
+		a.timer.Stop()
+	}
+	a.flush()
+}
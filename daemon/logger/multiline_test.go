@@ -0,0 +1,147 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewMultilineConfig(t *testing.T) {
+	c, err := NewMultilineConfig(map[string]string{"mode": "blocking"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != nil {
+		t.Fatal("expected no multiline config without multiline options")
+	}
+
+	c, err = NewMultilineConfig(map[string]string{multilinePatternOpt: `^\S`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Timeout != defaultMultilineTimeout {
+		t.Fatalf("expected default timeout, got %s", c.Timeout)
+	}
+
+	for _, cfg := range []map[string]string{
+		{multilinePatternOpt: "("},
+		{multilinePatternOpt: `^\S`, multilineTimeoutOpt: "soon"},
+		{multilinePatternOpt: `^\S`, multilineTimeoutOpt: "0s"},
+		{multilineTimeoutOpt: "1s"},
+	} {
+		if _, err := NewMultilineConfig(cfg); err == nil {
+			t.Fatalf("expected an error for %v", cfg)
+		}
+	}
+}
+
+func TestCopierMultiline(t *testing.T) {
+	stdout := strings.NewReader(strings.Join([]string{
+		"  orphan continuation",
+		"Exception in thread \"main\" java.lang.NullPointerException",
+		"\tat Main.run(Main.java:10)",
+		"\tat Main.main(Main.java:5)",
+		"done",
+		"",
+	}, "\n"))
+
+	var jsonBuf bytes.Buffer
+	jsonLog := &TestLoggerJSON{Encoder: json.NewEncoder(&jsonBuf)}
+
+	c := NewCopier(map[string]io.Reader{"stdout": stdout}, jsonLog)
+	c.SetMultiline(&MultilineConfig{Start: regexp.MustCompile(`^\S`), Timeout: time.Hour})
+	c.Run()
+	wait := make(chan struct{})
+	go func() {
+		c.Wait()
+		close(wait)
+	}()
+	select {
+	case <-time.After(10 * time.Second):
+		t.Fatal("Copier failed to do its work in 10 seconds")
+	case <-wait:
+	}
+
+	expected := []string{
+		"  orphan continuation",
+		"Exception in thread \"main\" java.lang.NullPointerException\n\tat Main.run(Main.java:10)\n\tat Main.main(Main.java:5)",
+		"done",
+	}
+	dec := json.NewDecoder(&jsonBuf)
+	for _, line := range expected {
+		var msg Message
+		if err := dec.Decode(&msg); err != nil {
+			t.Fatal(err)
+		}
+		if string(msg.Line) != line {
+			t.Fatalf("expected %q, got %q", line, msg.Line)
+		}
+		if msg.PLogMetaData != nil {
+			t.Fatalf("expected a complete message, got partial %v", msg.PLogMetaData)
+		}
+	}
+	var msg Message
+	if err := dec.Decode(&msg); err != io.EOF {
+		t.Fatalf("expected no more messages, got %q", msg.Line)
+	}
+}
+
+func TestMultilineAggregatorTimeout(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		written []*Message
+	)
+	write := func(msg *Message) {
+		mu.Lock()
+		written = append(written, msg)
+		mu.Unlock()
+	}
+	a := newMultilineAggregator(&MultilineConfig{Start: regexp.MustCompile(`^\S`), Timeout: 10 * time.Millisecond}, 32, write)
+	defer a.close()
+
+	for _, line := range []string{"Traceback (most recent call last):", "  File \"main.py\", line 1", "ValueError"} {
+		msg := NewMessage()
+		msg.Source = "stderr"
+		msg.Line = append(msg.Line, line...)
+		a.add(msg)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		n := len(written)
+		mu.Unlock()
+		if n >= 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for the entries to be written, got %d messages", n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// the first entry is larger than the buffer, and is split in partial messages
+	var line []byte
+	for i, msg := range written[:2] {
+		if msg.PLogMetaData == nil || msg.PLogMetaData.Ordinal != i+1 || msg.PLogMetaData.ID != written[0].PLogMetaData.ID {
+			t.Fatalf("expected partial message %d, got %+v", i+1, msg.PLogMetaData)
+		}
+		if msg.PLogMetaData.Last != (i == 1) {
+			t.Fatalf("unexpected last partial message %d", i+1)
+		}
+		line = append(line, msg.Line...)
+	}
+	if expected := "Traceback (most recent call last):\n  File \"main.py\", line 1"; string(line) != expected {
+		t.Fatalf("expected %q, got %q", expected, line)
+	}
+	if string(written[2].Line) != "ValueError" || written[2].PLogMetaData != nil {
+		t.Fatalf("unexpected message %q", written[2].Line)
+	}
+}
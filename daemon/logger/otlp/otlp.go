@@ -0,0 +1,513 @@
+// Package otlp provides the log driver for exporting container logs as
+// OpenTelemetry log records to an OTLP/HTTP receiver, such as the
+// OpenTelemetry collector.
+//
+// Records are batched and exported by a background worker, so that logging
+// a message only blocks while the buffer of the worker is full. Like every
+// driver, the logger is wrapped in a RingLogger when the container uses the
+// non-blocking log mode.
+package otlp // import "github.com/docker/docker/daemon/logger/otlp"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/loggerutils"
+	"github.com/docker/docker/dockerversion"
+	"github.com/docker/docker/pkg/pools"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	driverName = "otlp"
+
+	endpointKey           = "otlp-endpoint"
+	headersKey            = "otlp-headers"
+	compressionKey        = "otlp-compression"
+	timeoutKey            = "otlp-timeout"
+	batchSizeKey          = "otlp-batch-size"
+	batchIntervalKey      = "otlp-batch-interval"
+	retryWaitKey          = "otlp-retry-wait"
+	maxRetriesKey         = "otlp-max-retries"
+	caPathKey             = "otlp-capath"
+	insecureSkipVerifyKey = "otlp-insecureskipverify"
+	tagKey                = "tag"
+)
+
+const (
+	// logsPath is the path of the logs endpoint of an OTLP/HTTP receiver,
+	// appended to endpoints without a path.
+	logsPath = "/v1/logs"
+	// scopeName is the name of the instrumentation scope of the records.
+	scopeName = "github.com/docker/docker/daemon/logger/otlp"
+
+	compressionGzip = "gzip"
+	compressionNone = "none"
+
+	defaultTimeout       = 10 * time.Second
+	defaultBatchSize     = 512
+	defaultBatchInterval = time.Second
+	defaultRetryWait     = time.Second
+	defaultMaxRetries    = 5
+	// maxRetryWait is the maximum time waited between two attempts to
+	// export a batch.
+	maxRetryWait = 30 * time.Second
+	// maxResponseSize is the max amount that will be read from an http response
+	maxResponseSize = 1024
+	// streamChannelFactor is the number of records which can be queued for
+	// the worker, per record of a batch.
+	streamChannelFactor = 4
+)
+
+// Attributes of the records, and of the resource emitting them, following
+// the OpenTelemetry semantic conventions.
+const (
+	attrServiceName    = "service.name"
+	attrHostName       = "host.name"
+	attrContainerID    = "container.id"
+	attrContainerName  = "container.name"
+	attrContainerImage = "container.image.name"
+	attrContainerLabel = "container.label."
+	attrLogIOStream    = "log.iostream"
+)
+
+// levelAttr is the attribute of the messages used as the severity of the
+// records. It is set by the parse log option.
+const levelAttr = "level"
+
+// severityNumbers maps the usual level names to the severity numbers of
+// OpenTelemetry.
+var severityNumbers = map[string]int32{
+	"trace":    1,
+	"debug":    5,
+	"info":     9,
+	"warn":     13,
+	"warning":  13,
+	"error":    17,
+	"fatal":    21,
+	"critical": 21,
+}
+
+type config struct {
+	url           string
+	headers       map[string]string
+	gzip          bool
+	timeout       time.Duration
+	batchSize     int
+	batchInterval time.Duration
+	retryWait     time.Duration
+	maxRetries    int
+	tlsConfig     *tls.Config
+}
+
+type otlpLogger struct {
+	client   *http.Client
+	cfg      *config
+	resource []keyValue
+
+	// stream sends the records to the worker. lock protects the stream
+	// against Log calls after Close.
+	stream    chan *logRecord
+	lock      sync.RWMutex
+	closed    bool
+	closing   chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func init() {
+	if err := logger.RegisterLogDriver(driverName, New); err != nil {
+		logrus.Fatal(err)
+	}
+	if err := logger.RegisterLogOptValidator(driverName, ValidateLogOpt); err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+// New creates an otlp logger using the configuration passed in on the
+// context. The otlp-endpoint option is required.
+func New(info logger.Info) (logger.Logger, error) {
+	cfg, err := parseConfig(info.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := loggerutils.ParseLogTag(info, "{{.Name}}")
+	if err != nil {
+		return nil, err
+	}
+	hostname, err := info.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	resource := []keyValue{
+		{key: attrServiceName, value: tag},
+		{key: attrHostName, value: hostname},
+		{key: attrContainerID, value: info.ContainerID},
+		{key: attrContainerName, value: info.Name()},
+		{key: attrContainerImage, value: info.ImageName()},
+	}
+	labels := make([]string, 0, len(info.ContainerLabels))
+	for k := range info.ContainerLabels {
+		labels = append(labels, k)
+	}
+	sort.Strings(labels)
+	for _, k := range labels {
+		resource = append(resource, keyValue{key: attrContainerLabel + k, value: info.ContainerLabels[k]})
+	}
+
+	l := &otlpLogger{
+		client: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: cfg.tlsConfig,
+				Proxy:           http.ProxyFromEnvironment,
+			},
+			Timeout: cfg.timeout,
+		},
+		cfg:      cfg,
+		resource: resource,
+		stream:   make(chan *logRecord, streamChannelFactor*cfg.batchSize),
+		closing:  make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go l.worker()
+	return l, nil
+}
+
+func (l *otlpLogger) Log(msg *logger.Message) error {
+	r := &logRecord{
+		time:         msg.Timestamp,
+		observedTime: time.Now(),
+		body:         string(msg.Line),
+		attributes:   make([]keyValue, 0, len(msg.Attrs)+1),
+	}
+	r.attributes = append(r.attributes, keyValue{key: attrLogIOStream, value: msg.Source})
+	for _, a := range msg.Attrs {
+		if a.Key == levelAttr {
+			r.severityText = a.Value
+			r.severityNumber = severityNumbers[strings.ToLower(a.Value)]
+			continue
+		}
+		r.attributes = append(r.attributes, keyValue{key: a.Key, value: a.Value})
+	}
+	logger.PutMessage(msg)
+
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	if l.closed {
+		return fmt.Errorf("%s: driver is closed", driverName)
+	}
+	l.stream <- r
+	return nil
+}
+
+func (l *otlpLogger) Name() string {
+	return driverName
+}
+
+// Close exports the buffered records and stops the worker. Batches which
+// cannot be exported are no longer retried.
+func (l *otlpLogger) Close() error {
+	l.closeOnce.Do(func() {
+		// stop the retries first, Log may hold the read lock while the
+		// stream is full, until the worker is done with its current batch
+		close(l.closing)
+
+		l.lock.Lock()
+		l.closed = true
+		close(l.stream)
+		l.lock.Unlock()
+
+		<-l.done
+		l.client.CloseIdleConnections()
+	})
+	return nil
+}
+
+// worker exports the records by batches of batchSize records, or of the
+// records received during batchInterval.
+func (l *otlpLogger) worker() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.cfg.batchInterval)
+	defer ticker.Stop()
+
+	var records []*logRecord
+	for {
+		select {
+		case r, open := <-l.stream:
+			if !open {
+				l.export(records)
+				return
+			}
+			records = append(records, r)
+			if len(records) >= l.cfg.batchSize {
+				l.export(records)
+				records = nil
+			}
+		case <-ticker.C:
+			l.export(records)
+			records = nil
+		}
+	}
+}
+
+// export sends records to the receiver, retrying with an exponential backoff
+// when the receiver is unavailable. The records are dropped when they cannot
+// be exported after maxRetries retries.
+func (l *otlpLogger) export(records []*logRecord) {
+	if len(records) == 0 {
+		return
+	}
+	body, err := l.encode(records)
+	if err != nil {
+		logrus.WithError(err).WithField("module", "logger/otlp").Errorf("Failed to encode %d log records", len(records))
+		return
+	}
+
+	wait := l.cfg.retryWait
+	for attempt := 0; ; attempt++ {
+		err := l.post(body)
+		if err == nil {
+			return
+		}
+		expErr, retryable := err.(*exportError)
+		if retryable {
+			retryable = expErr.retryable
+		} else {
+			// network errors
+			retryable = true
+		}
+		if !retryable || attempt >= l.cfg.maxRetries || l.isClosing() {
+			logrus.WithError(err).WithField("module", "logger/otlp").Errorf("Failed to export %d log records, dropping them", len(records))
+			return
+		}
+		logrus.WithError(err).WithField("module", "logger/otlp").Warn("Error while exporting logs, retrying")
+
+		d := wait
+		if expErr != nil && expErr.retryAfter > 0 {
+			d = expErr.retryAfter
+		}
+		select {
+		case <-time.After(d):
+		case <-l.closing:
+		}
+		if wait *= 2; wait > maxRetryWait {
+			wait = maxRetryWait
+		}
+	}
+}
+
+func (l *otlpLogger) isClosing() bool {
+	select {
+	case <-l.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *otlpLogger) encode(records []*logRecord) ([]byte, error) {
+	body := encodeRequest(l.resource, scopeName, records)
+	if !l.cfg.gzip {
+		return body, nil
+	}
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// exportError is returned when the receiver rejects an export request.
+type exportError struct {
+	status     string
+	body       string
+	retryable  bool
+	retryAfter time.Duration
+}
+
+func (e *exportError) Error() string {
+	return fmt.Sprintf("%s: failed to export logs - %s - %s", driverName, e.status, e.body)
+}
+
+func (l *otlpLogger) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, l.cfg.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), l.cfg.timeout)
+	defer cancel()
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "docker/"+dockerversion.Version)
+	if l.cfg.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for k, v := range l.cfg.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		pools.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	expErr := &exportError{status: resp.Status, body: string(respBody)}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		expErr.retryable = true
+		if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s > 0 {
+			expErr.retryAfter = time.Duration(s) * time.Second
+		}
+	}
+	return expErr
+}
+
+// ValidateLogOpt looks for otlp specific log options.
+func ValidateLogOpt(cfg map[string]string) error {
+	for key := range cfg {
+		switch key {
+		case endpointKey:
+		case headersKey:
+		case compressionKey:
+		case timeoutKey:
+		case batchSizeKey:
+		case batchIntervalKey:
+		case retryWaitKey:
+		case maxRetriesKey:
+		case caPathKey:
+		case insecureSkipVerifyKey:
+		case tagKey:
+		default:
+			return fmt.Errorf("unknown log opt '%s' for %s log driver", key, driverName)
+		}
+	}
+	_, err := parseConfig(cfg)
+	return err
+}
+
+func parseConfig(cfg map[string]string) (*config, error) {
+	c := &config{
+		gzip:          true,
+		timeout:       defaultTimeout,
+		batchSize:     defaultBatchSize,
+		batchInterval: defaultBatchInterval,
+		retryWait:     defaultRetryWait,
+		maxRetries:    defaultMaxRetries,
+		tlsConfig:     &tls.Config{},
+	}
+
+	var err error
+	if c.url, err = parseEndpoint(cfg[endpointKey]); err != nil {
+		return nil, err
+	}
+
+	if s, ok := cfg[headersKey]; ok {
+		c.headers = make(map[string]string)
+		for _, h := range strings.Split(s, ",") {
+			i := strings.Index(h, "=")
+			if i < 0 || strings.TrimSpace(h[:i]) == "" {
+				return nil, errors.Errorf("%s: invalid header '%s' in %s, expected key=value", driverName, h, headersKey)
+			}
+			c.headers[strings.TrimSpace(h[:i])] = strings.TrimSpace(h[i+1:])
+		}
+	}
+
+	if s, ok := cfg[compressionKey]; ok {
+		switch s {
+		case compressionGzip:
+		case compressionNone:
+			c.gzip = false
+		default:
+			return nil, errors.Errorf("%s: invalid value for %s: %s, supported values are %s and %s", driverName, compressionKey, s, compressionGzip, compressionNone)
+		}
+	}
+
+	for key, d := range map[string]*time.Duration{
+		timeoutKey:       &c.timeout,
+		batchIntervalKey: &c.batchInterval,
+		retryWaitKey:     &c.retryWait,
+	} {
+		s, ok := cfg[key]
+		if !ok {
+			continue
+		}
+		if *d, err = time.ParseDuration(s); err != nil || *d <= 0 {
+			return nil, errors.Errorf("%s: invalid value for %s: %s, must be a positive duration", driverName, key, s)
+		}
+	}
+
+	if s, ok := cfg[batchSizeKey]; ok {
+		if c.batchSize, err = strconv.Atoi(s); err != nil || c.batchSize <= 0 {
+			return nil, errors.Errorf("%s: invalid value for %s: %s, must be a positive integer", driverName, batchSizeKey, s)
+		}
+	}
+	if s, ok := cfg[maxRetriesKey]; ok {
+		if c.maxRetries, err = strconv.Atoi(s); err != nil || c.maxRetries < 0 {
+			return nil, errors.Errorf("%s: invalid value for %s: %s, must be a non-negative integer", driverName, maxRetriesKey, s)
+		}
+	}
+
+	if s, ok := cfg[insecureSkipVerifyKey]; ok {
+		if c.tlsConfig.InsecureSkipVerify, err = strconv.ParseBool(s); err != nil {
+			return nil, errors.Wrapf(err, "%s: invalid value for %s", driverName, insecureSkipVerifyKey)
+		}
+	}
+	if caPath, ok := cfg[caPathKey]; ok {
+		caCert, err := ioutil.ReadFile(caPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s: failed to read %s", driverName, caPathKey)
+		}
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(caCert) {
+			return nil, errors.Errorf("%s: no certificate found in %s", driverName, caPath)
+		}
+		c.tlsConfig.RootCAs = caPool
+	}
+	return c, nil
+}
+
+// parseEndpoint returns the URL of the logs endpoint of the receiver at
+// endpoint. The path of the logs endpoint is added to URLs without a path.
+func parseEndpoint(endpoint string) (string, error) {
+	if endpoint == "" {
+		return "", errors.Errorf("%s: %s is expected", driverName, endpointKey)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", errors.Errorf("%s: invalid value for %s: %s, expected format scheme://host:port[/path]", driverName, endpointKey, endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = logsPath
+	}
+	return u.String(), nil
+}
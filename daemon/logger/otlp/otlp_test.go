@@ -0,0 +1,390 @@
+package otlp // import "github.com/docker/docker/daemon/logger/otlp"
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/gogo/protobuf/proto"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
+)
+
+// receivedRecord is a log record decoded by the receiver stub.
+type receivedRecord struct {
+	Time         int64
+	Severity     uint64
+	SeverityText string
+	Body         string
+	Attributes   map[string]string
+}
+
+// receiverStub is an OTLP/HTTP receiver decoding the log records of the
+// export requests. It answers the first failures requests with status.
+type receiverStub struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	status   int
+	failures int
+	requests int
+	headers  http.Header
+	resource map[string]string
+	records  []receivedRecord
+}
+
+func newReceiverStub(t *testing.T) (*receiverStub, *httptest.Server) {
+	r := &receiverStub{t: t}
+	return r, httptest.NewServer(r)
+}
+
+func (r *receiverStub) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests++
+	if r.requests <= r.failures {
+		w.WriteHeader(r.status)
+		return
+	}
+	if req.URL.Path != logsPath || req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/x-protobuf" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	body := req.Body
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		gr, err := gzip.NewReader(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = gr
+	}
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.headers = req.Header
+	r.decodeRequest(b)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (r *receiverStub) received() (map[string]string, []receivedRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.resource, append([]receivedRecord(nil), r.records...)
+}
+
+// field is a field of an encoded message. value holds the bytes of
+// length-delimited fields, and the value of the other fields.
+type field struct {
+	number int
+	value  []byte
+	scalar uint64
+}
+
+// fields calls f for each field of the message encoded in b. It runs in the
+// goroutine of the handler, so it must not stop the test.
+func (r *receiverStub) fields(b []byte, f func(field)) {
+	for len(b) > 0 && !r.t.Failed() {
+		key, n := proto.DecodeVarint(b)
+		if !assert.Check(r.t, n > 0) {
+			return
+		}
+		b = b[n:]
+		fd := field{number: int(key >> 3)}
+		switch key & 7 {
+		case wireVarint:
+			fd.scalar, n = proto.DecodeVarint(b)
+			if !assert.Check(r.t, n > 0) {
+				return
+			}
+			b = b[n:]
+		case wireFixed64:
+			if !assert.Check(r.t, len(b) >= 8) {
+				return
+			}
+			fd.scalar = binary.LittleEndian.Uint64(b)
+			b = b[8:]
+		case wireBytes:
+			l, n := proto.DecodeVarint(b)
+			if !assert.Check(r.t, n > 0 && uint64(len(b)-n) >= l) {
+				return
+			}
+			fd.value = b[n : n+int(l)]
+			b = b[n+int(l):]
+		default:
+			r.t.Errorf("unexpected wire type %d", key&7)
+			return
+		}
+		f(fd)
+	}
+}
+
+func (r *receiverStub) keyValue(b []byte) (key, value string) {
+	r.fields(b, func(fd field) {
+		switch fd.number {
+		case fieldKeyValueKey:
+			key = string(fd.value)
+		case fieldKeyValueValue:
+			value = r.stringValue(fd.value)
+		}
+	})
+	return key, value
+}
+
+func (r *receiverStub) stringValue(b []byte) (s string) {
+	r.fields(b, func(fd field) {
+		assert.Check(r.t, is.Equal(fd.number, fieldAnyValueStringValue))
+		s = string(fd.value)
+	})
+	return s
+}
+
+func (r *receiverStub) decodeRequest(b []byte) {
+	r.fields(b, func(fd field) {
+		assert.Check(r.t, is.Equal(fd.number, fieldRequestResourceLogs))
+		r.fields(fd.value, func(fd field) {
+			switch fd.number {
+			case fieldResourceLogsResource:
+				r.resource = make(map[string]string)
+				r.fields(fd.value, func(fd field) {
+					k, v := r.keyValue(fd.value)
+					r.resource[k] = v
+				})
+			case fieldResourceLogsScopeLogs:
+				r.fields(fd.value, func(fd field) {
+					if fd.number == fieldScopeLogsLogRecords {
+						r.records = append(r.records, r.decodeRecord(fd.value))
+					}
+				})
+			}
+		})
+	})
+}
+
+func (r *receiverStub) decodeRecord(b []byte) receivedRecord {
+	rec := receivedRecord{Attributes: make(map[string]string)}
+	r.fields(b, func(fd field) {
+		switch fd.number {
+		case fieldRecordTimeUnixNano:
+			rec.Time = int64(fd.scalar)
+		case fieldRecordObservedTimeUnixNano:
+			assert.Check(r.t, fd.scalar != 0)
+		case fieldRecordSeverityNumber:
+			rec.Severity = fd.scalar
+		case fieldRecordSeverityText:
+			rec.SeverityText = string(fd.value)
+		case fieldRecordBody:
+			rec.Body = r.stringValue(fd.value)
+		case fieldRecordAttributes:
+			k, v := r.keyValue(fd.value)
+			rec.Attributes[k] = v
+		default:
+			r.t.Errorf("unexpected field %d in log record", fd.number)
+		}
+	})
+	return rec
+}
+
+func newTestLogger(t *testing.T, cfg map[string]string) logger.Logger {
+	info := logger.Info{
+		Config:             cfg,
+		ContainerID:        "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657",
+		ContainerName:      "/container_name",
+		ContainerImageID:   "sha256:fe96b2d25aba2d2e2cd9b7f3ea9f0f8a9b0e7e0b8a8b8c8d8e8f8a8b8c8d8e8f",
+		ContainerImageName: "busybox:latest",
+		ContainerLabels:    map[string]string{"com.example.team": "storage", "tier": "backend"},
+	}
+	l, err := New(info)
+	assert.NilError(t, err)
+	return l
+}
+
+func TestLog(t *testing.T) {
+	receiver, server := newReceiverStub(t)
+	defer server.Close()
+
+	l := newTestLogger(t, map[string]string{
+		endpointKey:      server.URL,
+		headersKey:       "Authorization=Bearer token, X-Tenant=ops",
+		batchIntervalKey: "10ms",
+	})
+
+	ts := time.Date(2020, 5, 1, 10, 0, 0, 42, time.UTC)
+	assert.NilError(t, l.Log(&logger.Message{Line: []byte("hello"), Source: "stdout", Timestamp: ts}))
+	assert.NilError(t, l.Log(&logger.Message{
+		Line:      []byte(`{"level": "error", "msg": "failed"}`),
+		Source:    "stderr",
+		Timestamp: ts,
+		Attrs:     []backend.LogAttr{{Key: "level", Value: "error"}, {Key: "msg", Value: "failed"}},
+	}))
+
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		if _, records := receiver.received(); len(records) < 2 {
+			return poll.Continue("received %d records", len(records))
+		}
+		return poll.Success()
+	}, poll.WithDelay(10*time.Millisecond), poll.WithTimeout(10*time.Second))
+	assert.NilError(t, l.Close())
+
+	resource, records := receiver.received()
+	assert.Check(t, is.DeepEqual(resource, map[string]string{
+		attrServiceName:                    "container_name",
+		attrHostName:                       resource[attrHostName],
+		attrContainerID:                    "a7317399f3f857173c6179d44823594f8294678dea9999662e5c625b5a1c7657",
+		attrContainerName:                  "container_name",
+		attrContainerImage:                 "busybox:latest",
+		"container.label.com.example.team": "storage",
+		"container.label.tier":             "backend",
+	}))
+	assert.Check(t, resource[attrHostName] != "")
+
+	assert.Check(t, is.DeepEqual(records, []receivedRecord{
+		{
+			Time:       ts.UnixNano(),
+			Body:       "hello",
+			Attributes: map[string]string{attrLogIOStream: "stdout"},
+		},
+		{
+			Time:         ts.UnixNano(),
+			Severity:     17,
+			SeverityText: "error",
+			Body:         `{"level": "error", "msg": "failed"}`,
+			Attributes:   map[string]string{attrLogIOStream: "stderr", "msg": "failed"},
+		},
+	}))
+
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	assert.Check(t, is.Equal(receiver.headers.Get("Authorization"), "Bearer token"))
+	assert.Check(t, is.Equal(receiver.headers.Get("X-Tenant"), "ops"))
+	assert.Check(t, is.Equal(receiver.headers.Get("Content-Encoding"), "gzip"))
+}
+
+func TestLogBatches(t *testing.T) {
+	receiver, server := newReceiverStub(t)
+	defer server.Close()
+
+	l := newTestLogger(t, map[string]string{
+		endpointKey:      server.URL + "/",
+		compressionKey:   compressionNone,
+		batchSizeKey:     "10",
+		batchIntervalKey: "1h",
+	})
+	for i := 0; i < 25; i++ {
+		assert.NilError(t, l.Log(&logger.Message{Line: []byte("line"), Source: "stdout", Timestamp: time.Now()}))
+	}
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		if _, records := receiver.received(); len(records) < 20 {
+			return poll.Continue("received %d records", len(records))
+		}
+		return poll.Success()
+	}, poll.WithDelay(10*time.Millisecond), poll.WithTimeout(10*time.Second))
+
+	// the last records are exported on close
+	assert.NilError(t, l.Close())
+	_, records := receiver.received()
+	assert.Check(t, is.Len(records, 25))
+
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	assert.Check(t, is.Equal(receiver.requests, 3))
+	assert.Check(t, is.Equal(receiver.headers.Get("Content-Encoding"), ""))
+
+	assert.Check(t, is.ErrorContains(l.Log(&logger.Message{Line: []byte("line")}), "closed"))
+}
+
+func TestLogRetry(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		status   int
+		failures int
+		expected int
+	}{
+		{name: "retryable", status: http.StatusServiceUnavailable, failures: 2, expected: 1},
+		{name: "too many failures", status: http.StatusTooManyRequests, failures: 4, expected: 0},
+		{name: "not retryable", status: http.StatusBadRequest, failures: 1, expected: 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			receiver, server := newReceiverStub(t)
+			defer server.Close()
+			receiver.status = tc.status
+			receiver.failures = tc.failures
+
+			l := newTestLogger(t, map[string]string{
+				endpointKey:      server.URL,
+				batchSizeKey:     "1",
+				retryWaitKey:     "1ms",
+				maxRetriesKey:    "2",
+				batchIntervalKey: "1h",
+			})
+			assert.NilError(t, l.Log(&logger.Message{Line: []byte("line"), Source: "stdout", Timestamp: time.Now()}))
+
+			expectedRequests := tc.failures + tc.expected
+			if expectedRequests > 3 {
+				expectedRequests = 3
+			}
+			poll.WaitOn(t, func(poll.LogT) poll.Result {
+				receiver.mu.Lock()
+				defer receiver.mu.Unlock()
+				if receiver.requests < expectedRequests {
+					return poll.Continue("received %d requests", receiver.requests)
+				}
+				return poll.Success()
+			}, poll.WithDelay(10*time.Millisecond), poll.WithTimeout(10*time.Second))
+			assert.NilError(t, l.Close())
+
+			_, records := receiver.received()
+			assert.Check(t, is.Len(records, tc.expected))
+			receiver.mu.Lock()
+			defer receiver.mu.Unlock()
+			assert.Check(t, is.Equal(receiver.requests, expectedRequests))
+		})
+	}
+}
+
+func TestValidateLogOpt(t *testing.T) {
+	for _, tc := range []struct {
+		cfg map[string]string
+		err string
+	}{
+		{cfg: map[string]string{endpointKey: "http://localhost:4318", tagKey: "{{.Name}}", maxRetriesKey: "0"}},
+		{cfg: map[string]string{}, err: "otlp-endpoint is expected"},
+		{cfg: map[string]string{endpointKey: "localhost:4318"}, err: "expected format"},
+		{cfg: map[string]string{endpointKey: "http://localhost:4318", "otlp-foo": "bar"}, err: "unknown log opt"},
+		{cfg: map[string]string{endpointKey: "http://localhost:4318", headersKey: "foo"}, err: "invalid header"},
+		{cfg: map[string]string{endpointKey: "http://localhost:4318", compressionKey: "zstd"}, err: "invalid value for otlp-compression"},
+		{cfg: map[string]string{endpointKey: "http://localhost:4318", batchSizeKey: "0"}, err: "invalid value for otlp-batch-size"},
+		{cfg: map[string]string{endpointKey: "http://localhost:4318", retryWaitKey: "1"}, err: "invalid value for otlp-retry-wait"},
+	} {
+		err := ValidateLogOpt(tc.cfg)
+		if tc.err == "" {
+			assert.Check(t, err, tc.cfg)
+		} else {
+			assert.Check(t, is.ErrorContains(err, tc.err), tc.cfg)
+		}
+	}
+}
+
+func TestParseEndpoint(t *testing.T) {
+	for endpoint, expected := range map[string]string{
+		"http://collector:4318":            "http://collector:4318/v1/logs",
+		"https://collector:4318/":          "https://collector:4318/v1/logs",
+		"https://collector/custom/v1/logs": "https://collector/custom/v1/logs",
+	} {
+		u, err := parseEndpoint(endpoint)
+		assert.Check(t, err)
+		assert.Check(t, is.Equal(u, expected))
+	}
+}
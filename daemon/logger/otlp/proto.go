@@ -0,0 +1,137 @@
+package otlp // import "github.com/docker/docker/daemon/logger/otlp"
+
+import (
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// The messages of the OTLP logs protocol are encoded by hand, the driver
+// only needs a small subset of opentelemetry-proto. The field numbers are
+// the ones of opentelemetry/proto/collector/logs/v1/logs_service.proto and
+// its dependencies.
+const (
+	// ExportLogsServiceRequest
+	fieldRequestResourceLogs = 1
+
+	// ResourceLogs
+	fieldResourceLogsResource  = 1
+	fieldResourceLogsScopeLogs = 2
+
+	// Resource
+	fieldResourceAttributes = 1
+
+	// ScopeLogs
+	fieldScopeLogsScope      = 1
+	fieldScopeLogsLogRecords = 2
+
+	// InstrumentationScope
+	fieldScopeName = 1
+
+	// LogRecord
+	fieldRecordTimeUnixNano         = 1
+	fieldRecordSeverityNumber       = 2
+	fieldRecordSeverityText         = 3
+	fieldRecordBody                 = 5
+	fieldRecordAttributes           = 6
+	fieldRecordObservedTimeUnixNano = 11
+
+	// KeyValue
+	fieldKeyValueKey   = 1
+	fieldKeyValueValue = 2
+
+	// AnyValue
+	fieldAnyValueStringValue = 1
+)
+
+// Protobuf wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+// keyValue is an attribute of a resource or of a log record. Only string
+// values are supported.
+type keyValue struct {
+	key   string
+	value string
+}
+
+// logRecord is a log message, as exported to the OTLP receiver.
+type logRecord struct {
+	time           time.Time
+	observedTime   time.Time
+	severityNumber int32
+	severityText   string
+	body           string
+	attributes     []keyValue
+}
+
+func encodeTag(b *proto.Buffer, field int, wireType int) {
+	b.EncodeVarint(uint64(field)<<3 | uint64(wireType))
+}
+
+func encodeString(b *proto.Buffer, field int, s string) {
+	encodeTag(b, field, wireBytes)
+	b.EncodeStringBytes(s)
+}
+
+// encodeMessage encodes the message written by f as the field of b.
+func encodeMessage(b *proto.Buffer, field int, f func(*proto.Buffer)) {
+	m := proto.NewBuffer(nil)
+	f(m)
+	encodeTag(b, field, wireBytes)
+	b.EncodeRawBytes(m.Bytes())
+}
+
+func encodeKeyValues(b *proto.Buffer, field int, kvs []keyValue) {
+	for _, kv := range kvs {
+		encodeMessage(b, field, func(m *proto.Buffer) {
+			encodeString(m, fieldKeyValueKey, kv.key)
+			encodeMessage(m, fieldKeyValueValue, func(v *proto.Buffer) {
+				encodeString(v, fieldAnyValueStringValue, kv.value)
+			})
+		})
+	}
+}
+
+func encodeLogRecord(b *proto.Buffer, r *logRecord) {
+	encodeTag(b, fieldRecordTimeUnixNano, wireFixed64)
+	b.EncodeFixed64(uint64(r.time.UnixNano()))
+	if r.severityNumber != 0 {
+		encodeTag(b, fieldRecordSeverityNumber, wireVarint)
+		b.EncodeVarint(uint64(r.severityNumber))
+	}
+	if r.severityText != "" {
+		encodeString(b, fieldRecordSeverityText, r.severityText)
+	}
+	encodeMessage(b, fieldRecordBody, func(v *proto.Buffer) {
+		encodeString(v, fieldAnyValueStringValue, r.body)
+	})
+	encodeKeyValues(b, fieldRecordAttributes, r.attributes)
+	encodeTag(b, fieldRecordObservedTimeUnixNano, wireFixed64)
+	b.EncodeFixed64(uint64(r.observedTime.UnixNano()))
+}
+
+// encodeRequest encodes an ExportLogsServiceRequest holding records, all
+// emitted by the resource with the given attributes.
+func encodeRequest(resource []keyValue, scope string, records []*logRecord) []byte {
+	b := proto.NewBuffer(nil)
+	encodeMessage(b, fieldRequestResourceLogs, func(rl *proto.Buffer) {
+		encodeMessage(rl, fieldResourceLogsResource, func(res *proto.Buffer) {
+			encodeKeyValues(res, fieldResourceAttributes, resource)
+		})
+		encodeMessage(rl, fieldResourceLogsScopeLogs, func(sl *proto.Buffer) {
+			encodeMessage(sl, fieldScopeLogsScope, func(s *proto.Buffer) {
+				encodeString(s, fieldScopeName, scope)
+			})
+			for _, r := range records {
+				encodeMessage(sl, fieldScopeLogsLogRecords, func(m *proto.Buffer) {
+					encodeLogRecord(m, r)
+				})
+			}
+		})
+	})
+	return b.Bytes()
+}
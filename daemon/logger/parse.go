@@ -0,0 +1,91 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/docker/docker/api/types/backend"
+	"github.com/pkg/errors"
+)
+
+// Log options configuring the parsing of the lines logged by a Copier.
+const (
+	parseOpt       = "parse"
+	parseFieldsOpt = "parse-fields"
+
+	parseFormatJSON = "json"
+)
+
+// defaultParseFields are the fields of the lines stored as attributes of the
+// messages when the parse-fields log option is not set.
+var defaultParseFields = []string{"level", "msg", "trace_id"}
+
+// LineParser decodes structured log lines, and adds selected fields of the
+// lines to the attributes of the messages.
+type LineParser struct {
+	fields []string
+}
+
+// NewLineParser creates a LineParser from the log options of a container. It
+// returns nil if parsing is not enabled.
+func NewLineParser(cfg map[string]string) (*LineParser, error) {
+	format, ok := cfg[parseOpt]
+	if !ok {
+		if _, ok := cfg[parseFieldsOpt]; ok {
+			return nil, errors.Errorf("%s requires %s to be set", parseFieldsOpt, parseOpt)
+		}
+		return nil, nil
+	}
+	if format != parseFormatJSON {
+		return nil, errors.Errorf("invalid value for %s: %s, only %s is supported", parseOpt, format, parseFormatJSON)
+	}
+
+	fields := defaultParseFields
+	if s, ok := cfg[parseFieldsOpt]; ok {
+		fields = strings.Split(s, ",")
+	}
+	p := &LineParser{}
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			return nil, errors.Errorf("invalid value for %s: %s, field names cannot be empty", parseFieldsOpt, cfg[parseFieldsOpt])
+		}
+		p.fields = append(p.fields, f)
+	}
+	return p, nil
+}
+
+// validateParseOpts validates the parsing options in cfg.
+func validateParseOpts(cfg map[string]string) error {
+	_, err := NewLineParser(cfg)
+	return err
+}
+
+// Parse adds the selected fields of the line of msg to its attributes. Lines
+// which are not JSON objects, and partial messages, are left untouched.
+// String values are stored as is, other values as JSON.
+func (p *LineParser) Parse(msg *Message) {
+	if msg.PLogMetaData != nil {
+		return
+	}
+	line := bytes.TrimSpace(msg.Line)
+	if len(line) == 0 || line[0] != '{' {
+		return
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(line, &obj); err != nil {
+		return
+	}
+	for _, k := range p.fields {
+		raw, ok := obj[k]
+		if !ok {
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			value = string(raw)
+		}
+		msg.Attrs = append(msg.Attrs, backend.LogAttr{Key: k, Value: value})
+	}
+}
@@ -0,0 +1,78 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types/backend"
+)
+
+func TestNewLineParser(t *testing.T) {
+	p, err := NewLineParser(map[string]string{"mode": "blocking"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != nil {
+		t.Fatal("expected no parser without parse options")
+	}
+
+	for _, cfg := range []map[string]string{
+		{parseOpt: "logfmt"},
+		{parseFieldsOpt: "level"},
+		{parseOpt: "json", parseFieldsOpt: "level,,msg"},
+	} {
+		if _, err := NewLineParser(cfg); err == nil {
+			t.Fatalf("expected an error for %v", cfg)
+		}
+	}
+}
+
+func TestLineParserParse(t *testing.T) {
+	p, err := NewLineParser(map[string]string{parseOpt: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		line     string
+		partial  bool
+		expected []backend.LogAttr
+	}{
+		{
+			line: `{"msg": "started", "level": "info", "trace_id": "abc", "port": 8080}`,
+			expected: []backend.LogAttr{
+				{Key: "level", Value: "info"},
+				{Key: "msg", Value: "started"},
+				{Key: "trace_id", Value: "abc"},
+			},
+		},
+		{
+			line:     `{"level": 3, "msg": {"text": "nested"}}`,
+			expected: []backend.LogAttr{{Key: "level", Value: "3"}, {Key: "msg", Value: `{"text": "nested"}`}},
+		},
+		{line: `plain text`},
+		{line: `{"level": "info"`},
+		{line: `["level"]`},
+		{line: `{"level": "info"}`, partial: true},
+	} {
+		msg := &Message{Line: []byte(tc.line)}
+		if tc.partial {
+			msg.PLogMetaData = &backend.PartialLogMetaData{ID: "id", Ordinal: 1}
+		}
+		p.Parse(msg)
+		if !reflect.DeepEqual(msg.Attrs, tc.expected) {
+			t.Fatalf("unexpected attributes for %q: %v", tc.line, msg.Attrs)
+		}
+	}
+
+	p, err = NewLineParser(map[string]string{parseOpt: "json", parseFieldsOpt: "port, level"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := &Message{Line: []byte(`{"msg": "started", "level": "info", "port": 8080}`)}
+	p.Parse(msg)
+	expected := []backend.LogAttr{{Key: "port", Value: "8080"}, {Key: "level", Value: "info"}}
+	if !reflect.DeepEqual(msg.Attrs, expected) {
+		t.Fatalf("expected %v, got %v", expected, msg.Attrs)
+	}
+}
@@ -0,0 +1,140 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+// Log options configuring the rate limit of the messages logged by a Copier.
+const (
+	rateLimitLinesOpt      = "rate-limit-lines"
+	rateLimitLinesBurstOpt = "rate-limit-lines-burst"
+	rateLimitBytesOpt      = "rate-limit-bytes"
+	rateLimitBytesBurstOpt = "rate-limit-bytes-burst"
+)
+
+// suppressedReportInterval is the interval at which the number of messages
+// dropped by the rate limit is written to the log.
+var suppressedReportInterval = 10 * time.Second
+
+// RateLimiter limits the number of lines and bytes per second logged by a
+// Copier. The messages over the limit are dropped and counted, so that
+// the number of suppressed messages can be reported in the log.
+type RateLimiter struct {
+	lines      *rate.Limiter
+	bytes      *rate.Limiter
+	bytesBurst int
+
+	mu         sync.Mutex
+	suppressed map[string]int // number of suppressed messages by source
+}
+
+// NewRateLimiter creates a RateLimiter from the log options of a container.
+// It returns nil if no rate limit is configured.
+func NewRateLimiter(cfg map[string]string) (*RateLimiter, error) {
+	var l RateLimiter
+	if s, ok := cfg[rateLimitLinesOpt]; ok {
+		lines, err := strconv.Atoi(s)
+		if err != nil || lines <= 0 {
+			return nil, errors.Errorf("invalid value for %s: %s, must be a positive number of lines per second", rateLimitLinesOpt, s)
+		}
+		burst := lines
+		if s, ok := cfg[rateLimitLinesBurstOpt]; ok {
+			burst, err = strconv.Atoi(s)
+			if err != nil || burst <= 0 {
+				return nil, errors.Errorf("invalid value for %s: %s, must be a positive number of lines", rateLimitLinesBurstOpt, s)
+			}
+		}
+		l.lines = rate.NewLimiter(rate.Limit(lines), burst)
+	} else if _, ok := cfg[rateLimitLinesBurstOpt]; ok {
+		return nil, errors.Errorf("%s requires %s to be set", rateLimitLinesBurstOpt, rateLimitLinesOpt)
+	}
+
+	if s, ok := cfg[rateLimitBytesOpt]; ok {
+		bytes, err := units.RAMInBytes(s)
+		if err != nil || bytes <= 0 {
+			return nil, errors.Errorf("invalid value for %s: %s, must be a positive size per second", rateLimitBytesOpt, s)
+		}
+		burst := bytes
+		if s, ok := cfg[rateLimitBytesBurstOpt]; ok {
+			burst, err = units.RAMInBytes(s)
+			if err != nil || burst <= 0 {
+				return nil, errors.Errorf("invalid value for %s: %s, must be a positive size", rateLimitBytesBurstOpt, s)
+			}
+		}
+		l.bytesBurst = int(burst)
+		l.bytes = rate.NewLimiter(rate.Limit(bytes), l.bytesBurst)
+	} else if _, ok := cfg[rateLimitBytesBurstOpt]; ok {
+		return nil, errors.Errorf("%s requires %s to be set", rateLimitBytesBurstOpt, rateLimitBytesOpt)
+	}
+
+	if l.lines == nil && l.bytes == nil {
+		return nil, nil
+	}
+	l.suppressed = make(map[string]int)
+	return &l, nil
+}
+
+// Allow reports whether a message of size bytes from source may be logged
+// now. If it may not, the message is counted as suppressed.
+func (l *RateLimiter) Allow(source string, size int) bool {
+	if l.allow(size) {
+		return true
+	}
+	l.mu.Lock()
+	l.suppressed[source]++
+	l.mu.Unlock()
+	logMessagesSuppressedCount.Inc(1)
+	logBytesSuppressedCount.Inc(float64(size))
+	return false
+}
+
+func (l *RateLimiter) allow(size int) bool {
+	now := time.Now()
+	var lines *rate.Reservation
+	if l.lines != nil {
+		lines = l.lines.ReserveN(now, 1)
+		if lines.DelayFrom(now) > 0 {
+			lines.CancelAt(now)
+			return false
+		}
+	}
+	if l.bytes != nil {
+		if size > l.bytesBurst {
+			// larger messages would never be allowed otherwise
+			size = l.bytesBurst
+		}
+		if r := l.bytes.ReserveN(now, size); r.DelayFrom(now) > 0 {
+			r.CancelAt(now)
+			if lines != nil {
+				lines.CancelAt(now)
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// suppressedMessages returns the number of messages suppressed since the
+// last call by source, and resets them.
+func (l *RateLimiter) suppressedMessages() map[string]int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.suppressed) == 0 {
+		return nil
+	}
+	suppressed := l.suppressed
+	l.suppressed = make(map[string]int)
+	return suppressed
+}
+
+// validateRateLimitOpts validates the rate limit options in cfg.
+func validateRateLimitOpts(cfg map[string]string) error {
+	_, err := NewRateLimiter(cfg)
+	return err
+}
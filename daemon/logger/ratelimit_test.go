@@ -0,0 +1,114 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	l, err := NewRateLimiter(map[string]string{"mode": "blocking"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l != nil {
+		t.Fatal("expected no rate limiter without rate limit options")
+	}
+
+	for _, cfg := range []map[string]string{
+		{rateLimitLinesOpt: "0"},
+		{rateLimitLinesOpt: "ten"},
+		{rateLimitLinesOpt: "10", rateLimitLinesBurstOpt: "-1"},
+		{rateLimitLinesBurstOpt: "10"},
+		{rateLimitBytesOpt: "1x"},
+		{rateLimitBytesOpt: "1m", rateLimitBytesBurstOpt: "0"},
+		{rateLimitBytesBurstOpt: "1m"},
+	} {
+		if _, err := NewRateLimiter(cfg); err == nil {
+			t.Fatalf("expected an error for %v", cfg)
+		}
+	}
+}
+
+func TestRateLimiterBytes(t *testing.T) {
+	l, err := NewRateLimiter(map[string]string{rateLimitBytesOpt: "1", rateLimitBytesBurstOpt: "100"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// messages larger than the burst are allowed once the burst is available
+	if !l.Allow("stdout", 1000) {
+		t.Fatal("expected a message larger than the burst to be allowed")
+	}
+	if l.Allow("stdout", 10) {
+		t.Fatal("expected message to be over the rate limit")
+	}
+	if n := l.suppressedMessages()["stdout"]; n != 1 {
+		t.Fatalf("expected 1 suppressed message, got %d", n)
+	}
+	if l.suppressedMessages() != nil {
+		t.Fatal("expected suppressed messages to be reset")
+	}
+}
+
+func TestCopierRateLimit(t *testing.T) {
+	var stdout bytes.Buffer
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&stdout, "line %d\n", i)
+	}
+	// a partial message is dropped entirely
+	stdout.WriteString(strings.Repeat("a", 2*defaultBufSize) + "\n")
+
+	var jsonBuf bytes.Buffer
+	jsonLog := &TestLoggerJSON{Encoder: json.NewEncoder(&jsonBuf)}
+
+	limiter, err := NewRateLimiter(map[string]string{rateLimitLinesOpt: "1", rateLimitLinesBurstOpt: "10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCopier(map[string]io.Reader{"stdout": &stdout}, jsonLog)
+	c.SetRateLimiter(limiter)
+	c.Run()
+	wait := make(chan struct{})
+	go func() {
+		c.Wait()
+		close(wait)
+	}()
+	select {
+	case <-time.After(10 * time.Second):
+		t.Fatal("Copier failed to do its work in 10 seconds")
+	case <-wait:
+	}
+
+	var msgs []Message
+	dec := json.NewDecoder(&jsonBuf)
+	for {
+		var msg Message
+		if err := dec.Decode(&msg); err != nil {
+			if err == io.EOF {
+				break
+			}
+			t.Fatal(err)
+		}
+		msgs = append(msgs, msg)
+	}
+	if len(msgs) < 2 {
+		t.Fatalf("expected messages to be logged, got %d", len(msgs))
+	}
+	logged := len(msgs) - 1
+	for _, msg := range msgs[:logged] {
+		if !strings.HasPrefix(string(msg.Line), "line ") {
+			t.Fatalf("unexpected message %q", msg.Line)
+		}
+	}
+	var suppressed int
+	if _, err := fmt.Sscanf(string(msgs[logged].Line), "%d messages suppressed", &suppressed); err != nil {
+		t.Fatalf("expected the number of suppressed messages to be logged, got %q", msgs[logged].Line)
+	}
+	if logged+suppressed != 101 {
+		t.Fatalf("expected %d logged and %d suppressed messages to add up to 101", logged, suppressed)
+	}
+}
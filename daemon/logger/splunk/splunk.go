@@ -347,6 +347,7 @@ func (l *splunkLoggerInline) Log(msg *logger.Message) error {
 	event := *l.nullEvent
 	event.Line = string(msg.Line)
 	event.Source = msg.Source
+	event.Attrs = eventAttrs(event.Attrs, msg)
 
 	message.Event = &event
 	logger.PutMessage(msg)
@@ -365,12 +366,29 @@ func (l *splunkLoggerJSON) Log(msg *logger.Message) error {
 	}
 
 	event.Source = msg.Source
+	event.Attrs = eventAttrs(event.Attrs, msg)
 
 	message.Event = &event
 	logger.PutMessage(msg)
 	return l.queueMessageAsync(message)
 }
 
+// eventAttrs returns the attributes of the event of msg, which are the
+// attributes of the message on top of the ones of the logger.
+func eventAttrs(attrs map[string]string, msg *logger.Message) map[string]string {
+	if len(msg.Attrs) == 0 {
+		return attrs
+	}
+	merged := make(map[string]string, len(attrs)+len(msg.Attrs))
+	for k, v := range attrs {
+		merged[k] = v
+	}
+	for _, attr := range msg.Attrs {
+		merged[attr.Key] = attr.Value
+	}
+	return merged
+}
+
 func (l *splunkLoggerRaw) Log(msg *logger.Message) error {
 	// empty or whitespace-only messages are not accepted by HEC
 	if strings.TrimSpace(string(msg.Line)) == "" {
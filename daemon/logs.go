@@ -11,6 +11,7 @@ import (
 	timetypes "github.com/docker/docker/api/types/time"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/loggerutils/cache"
 	"github.com/docker/docker/errdefs"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -89,10 +90,22 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 	}
 
 	readConfig := logger.ReadConfig{
-		Since:  since,
-		Until:  until,
-		Tail:   tailLines,
-		Follow: follow,
+		Since:   since,
+		Until:   until,
+		Tail:    tailLines,
+		Follow:  follow,
+		Include: config.Include,
+		Exclude: config.Exclude,
+		Attrs:   config.Attrs,
+	}
+	if !config.ShowStdout {
+		readConfig.Source = "stderr"
+	} else if !config.ShowStderr {
+		readConfig.Source = "stdout"
+	}
+	filter, err := logger.NewMessageFilter(readConfig)
+	if err != nil {
+		return nil, false, err
 	}
 
 	logs := logReader.ReadLogs(readConfig)
@@ -142,6 +155,10 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 				if !ok {
 					return
 				}
+				// not all log drivers apply the filters themselves
+				if !filter.Match(msg) {
+					continue
+				}
 				m := msg.AsLogMessage() // just a pointer conversion, does not copy data
 
 				// there could be a case where the reader stops accepting
@@ -190,6 +207,8 @@ func (daemon *Daemon) mergeAndVerifyLogConfig(cfg *containertypes.LogConfig) err
 		}
 	}
 
+	cache.MergeDefaultLogConfig(cfg.Config, daemon.defaultLogConfig.Config)
+
 	return logger.ValidateLogOpts(cfg.Type, cfg.Config)
 }
 
@@ -3,6 +3,7 @@ package daemon // import "github.com/docker/docker/daemon"
 import (
 	"sync"
 
+	"github.com/docker/docker/daemon/stats"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/plugingetter"
 	"github.com/docker/docker/pkg/plugins"
@@ -115,6 +116,22 @@ func (ctr *stateCounter) Collect(ch chan<- prometheus.Metric) {
 	ch <- prometheus.MustNewConstMetric(ctr.desc, prometheus.GaugeValue, float64(stopped), "stopped")
 }
 
+// registerContainerMetrics exports the resource usage of the running
+// containers on the metrics endpoint, labelled by the container labels in
+// labels.
+func (daemon *Daemon) registerContainerMetrics(labels []string) error {
+	ns := metrics.NewNamespace("engine", "daemon", nil)
+	m, err := stats.NewContainerMetrics(ns, labels)
+	if err != nil {
+		return errors.Wrap(err, "invalid metrics-container-labels")
+	}
+	ns.Add(m)
+	metrics.Register(ns)
+
+	daemon.statsCollector.AddObserver(m)
+	return nil
+}
+
 func (d *Daemon) cleanupMetricsPlugins() {
 	ls := d.PluginStore.GetAllManagedPluginsByCap(metricsPluginType)
 	var wg sync.WaitGroup
@@ -18,10 +18,26 @@ func (daemon *Daemon) setStateCounter(c *container.Container) {
 	switch c.StateString() {
 	case "paused":
 		stateCtr.set(c.ID, "paused")
+		daemon.trackContainerStats(c, true)
 	case "running":
 		stateCtr.set(c.ID, "running")
+		daemon.trackContainerStats(c, true)
 	default:
 		stateCtr.set(c.ID, "stopped")
+		daemon.trackContainerStats(c, false)
+	}
+}
+
+// trackContainerStats starts or stops the continuous collection of the stats
+// of c, if enabled.
+func (daemon *Daemon) trackContainerStats(c *container.Container, track bool) {
+	if !daemon.trackStats {
+		return
+	}
+	if track {
+		daemon.statsCollector.Track(c)
+	} else {
+		daemon.statsCollector.Untrack(c)
 	}
 }
 
@@ -75,6 +91,9 @@ func (daemon *Daemon) ProcessEvent(id string, e libcontainerdtypes.EventType, ei
 				c.SetStopped(&exitStatus)
 				defer daemon.autoRemove(c)
 			}
+			crashLooping := c.RestartManager().CrashLooping()
+			enteredCrashLoop := crashLooping && !c.CrashLooping
+			c.CrashLooping = crashLooping
 			defer c.Unlock() // needs to be called before autoRemove
 
 			// cancel healthcheck here, they will be automatically
@@ -84,6 +103,11 @@ func (daemon *Daemon) ProcessEvent(id string, e libcontainerdtypes.EventType, ei
 				"exitCode": strconv.Itoa(int(ei.ExitCode)),
 			}
 			daemon.LogContainerEventWithAttributes(c, "die", attributes)
+			if enteredCrashLoop {
+				daemon.LogContainerEventWithAttributes(c, "crash-loop", map[string]string{
+					"restartCount": strconv.Itoa(c.RestartCount),
+				})
+			}
 			daemon.Cleanup(c)
 			daemon.setStateCounter(c)
 			cpErr := c.CheckpointTo(daemon.containersReplica)
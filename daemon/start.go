@@ -159,6 +159,7 @@ func (daemon *Daemon) containerStart(container *container.Container, checkpoint
 	if resetRestartManager {
 		container.ResetRestartManager(true)
 		container.HasBeenManuallyStopped = false
+		container.CrashLooping = false
 	}
 
 	if daemon.saveApparmorConfig(container); err != nil {
@@ -210,6 +211,7 @@ func (daemon *Daemon) containerStart(container *container.Container, checkpoint
 	container.SetRunning(pid, true)
 	container.HasBeenStartedBefore = true
 	daemon.setStateCounter(container)
+	daemon.imageService.UpdateLastUsed(container.ImageID)
 
 	daemon.initHealthMonitor(container)
 
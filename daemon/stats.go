@@ -4,14 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"runtime"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/backend"
+	timetypes "github.com/docker/docker/api/types/time"
 	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/api/types/versions/v1p20"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/stats"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/ioutils"
 )
 
@@ -30,6 +34,10 @@ func (daemon *Daemon) ContainerStats(ctx context.Context, prefixOrName string, c
 		return err
 	}
 
+	if config.Since != "" || config.Until != "" || config.Resolution != "" {
+		return daemon.containerStatsHistory(container, config)
+	}
+
 	// If the container is either not running or restarting and requires no stream, return an empty stats.
 	if (!container.IsRunning() || container.IsRestarting()) && !config.Stream {
 		return json.NewEncoder(config.OutStream).Encode(&types.StatsJSON{
@@ -53,6 +61,7 @@ func (daemon *Daemon) ContainerStats(ctx context.Context, prefixOrName string, c
 		ss.ID = container.ID
 		ss.PreCPUStats = preCPUStats
 		ss.PreRead = preRead
+		stats.SetCPUThrottling(&ss)
 		preCPUStats = ss.CPUStats
 		preRead = ss.Read
 		return &ss
@@ -130,6 +139,121 @@ func (daemon *Daemon) ContainerStats(ctx context.Context, prefixOrName string, c
 	}
 }
 
+// containerStatsHistory writes the samples of the stats history of the
+// container selected by config to its output stream.
+func (daemon *Daemon) containerStatsHistory(container *container.Container, config *backend.ContainerStatsConfig) error {
+	var since, until time.Time
+	if config.Since != "" {
+		s, n, err := timetypes.ParseTimestamps(config.Since, 0)
+		if err != nil {
+			return errdefs.InvalidParameter(err)
+		}
+		since = time.Unix(s, n)
+	}
+	if config.Until != "" && config.Until != "0" {
+		s, n, err := timetypes.ParseTimestamps(config.Until, 0)
+		if err != nil {
+			return errdefs.InvalidParameter(err)
+		}
+		until = time.Unix(s, n)
+	}
+	var resolution time.Duration
+	if config.Resolution != "" {
+		var err error
+		resolution, err = time.ParseDuration(config.Resolution)
+		if err != nil || resolution <= 0 {
+			return errdefs.InvalidParameter(fmt.Errorf("invalid resolution %q: must be a positive duration", config.Resolution))
+		}
+	}
+
+	enc := json.NewEncoder(config.OutStream)
+	for _, s := range daemon.statsHistory.Query(container.ID, since, until, resolution) {
+		s.Name = container.Name
+		s.ID = container.ID
+		if err := enc.Encode(&s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SystemStats writes the stats of the running containers matching
+// config.Filters, with their totals, to the output stream of config, once per
+// collection pass of the stats collector.
+func (daemon *Daemon) SystemStats(ctx context.Context, config *backend.SystemStatsConfig) error {
+	listOptions := &types.ContainerListOptions{Filters: config.Filters}
+	// validate the filters before the stream starts
+	if _, err := daemon.Containers(listOptions); err != nil {
+		return err
+	}
+
+	outStream := config.OutStream
+	if config.Stream {
+		wf := ioutils.NewWriteFlusher(outStream)
+		defer wf.Close()
+		wf.Flush()
+		outStream = wf
+	}
+	enc := json.NewEncoder(outStream)
+
+	updates := daemon.statsCollector.CollectAll()
+	defer daemon.statsCollector.UnsubscribeAll(updates)
+
+	previous := make(map[string]types.CPUStats)
+	previousRead := make(map[string]time.Time)
+	noStreamFirstFrame := true
+	for {
+		select {
+		case v, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			matching, err := daemon.Containers(listOptions)
+			if err != nil {
+				return err
+			}
+			selected := make(map[string]bool, len(matching))
+			for _, c := range matching {
+				selected[c.ID] = true
+			}
+
+			ss := types.SystemStats{Read: time.Now(), Containers: []types.StatsJSON{}}
+			current := make(map[string]types.CPUStats)
+			currentRead := make(map[string]time.Time)
+			for _, s := range v.([]types.StatsJSON) {
+				if !selected[s.ID] {
+					continue
+				}
+				s.PreCPUStats = previous[s.ID]
+				s.PreRead = previousRead[s.ID]
+				stats.SetCPUThrottling(&s)
+				current[s.ID] = s.CPUStats
+				currentRead[s.ID] = s.Read
+				ss.Containers = append(ss.Containers, s)
+			}
+			previous, previousRead = current, currentRead
+			ss.Totals = stats.Totals(ss.Containers)
+			ss.Totals.MemoryLimit = daemon.machineMemory
+
+			if !config.Stream && noStreamFirstFrame {
+				// prime the cpu stats so they aren't 0 in the final output
+				noStreamFirstFrame = false
+				continue
+			}
+
+			if err := enc.Encode(&ss); err != nil {
+				return err
+			}
+
+			if !config.Stream {
+				return nil
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
 func (daemon *Daemon) subscribeToContainerStats(c *container.Container) chan interface{} {
 	return daemon.statsCollector.Collect(c)
 }
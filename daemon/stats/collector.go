@@ -17,6 +17,9 @@ type Collector struct {
 	supervisor supervisor
 	interval   time.Duration
 	publishers map[*container.Container]*pubsub.Publisher
+	tracked    map[*container.Container]struct{}
+	observers  []Observer
+	passes     *pubsub.Publisher
 	bufReader  *bufio.Reader
 
 	// The following fields are not set on Windows currently.
@@ -29,6 +32,8 @@ func NewCollector(supervisor supervisor, interval time.Duration) *Collector {
 		interval:   interval,
 		supervisor: supervisor,
 		publishers: make(map[*container.Container]*pubsub.Publisher),
+		tracked:    make(map[*container.Container]struct{}),
+		passes:     pubsub.NewPublisher(100*time.Millisecond, 1024),
 		bufReader:  bufio.NewReaderSize(nil, 128),
 	}
 
@@ -42,6 +47,61 @@ type supervisor interface {
 	GetContainerStats(container *container.Container) (*types.StatsJSON, error)
 }
 
+// Observer is notified of the stats collected for the containers tracked by
+// a Collector.
+type Observer interface {
+	// Observe is called with each stats sample of a tracked container. The
+	// samples of the containers which are not running only have their Name
+	// and ID set. It must not call the Collector.
+	Observe(c *container.Container, stats types.StatsJSON)
+	// Forget is called when the container is no longer tracked.
+	Forget(c *container.Container)
+}
+
+// AddObserver adds an observer of the stats of the tracked containers.
+func (s *Collector) AddObserver(o Observer) {
+	s.m.Lock()
+	s.observers = append(s.observers, o)
+	s.m.Unlock()
+}
+
+// Track registers the container with the collector, so that its stats are
+// collected for the observers even if there is no subscriber.
+func (s *Collector) Track(c *container.Container) {
+	s.m.Lock()
+	s.tracked[c] = struct{}{}
+	s.m.Unlock()
+}
+
+// Untrack stops the collection of the stats of the container for the
+// observers.
+func (s *Collector) Untrack(c *container.Container) {
+	s.m.Lock()
+	_, exists := s.tracked[c]
+	delete(s.tracked, c)
+	observers := s.observers
+	s.m.Unlock()
+
+	if exists {
+		for _, o := range observers {
+			o.Forget(c)
+		}
+	}
+}
+
+// observe passes stats to the observers if c is still tracked. The lock is
+// held so that the observers are not notified after Forget.
+func (s *Collector) observe(c *container.Container, stats types.StatsJSON) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if _, exists := s.tracked[c]; !exists {
+		return
+	}
+	for _, o := range s.observers {
+		o.Observe(c, stats)
+	}
+}
+
 // Collect registers the container with the collector and adds it to
 // the event loop for collection on the specified interval returning
 // a channel for the subscriber to receive on.
@@ -65,6 +125,20 @@ func (s *Collector) StopCollection(c *container.Container) {
 		delete(s.publishers, c)
 	}
 	s.m.Unlock()
+	s.Untrack(c)
+}
+
+// CollectAll returns a channel for the subscriber to receive the stats of all
+// the running containers sampled in each collection pass, as a
+// []types.StatsJSON. The containers are sampled if they are tracked or if
+// they have subscribers.
+func (s *Collector) CollectAll() chan interface{} {
+	return s.passes.Subscribe()
+}
+
+// UnsubscribeAll removes a subscriber of CollectAll.
+func (s *Collector) UnsubscribeAll(ch chan interface{}) {
+	s.passes.Evict(ch)
 }
 
 // Unsubscribe removes a specific subscriber from receiving updates for a container's stats.
@@ -104,8 +178,17 @@ func (s *Collector) Run() {
 			// copy pointers here to release the lock ASAP
 			pairs = append(pairs, publishersPair{container, publisher})
 		}
+		for container := range s.tracked {
+			if _, exists := s.publishers[container]; !exists {
+				// tracked containers without subscribers have no publisher
+				pairs = append(pairs, publishersPair{container, nil})
+			}
+		}
 		s.m.Unlock()
 		if len(pairs) == 0 {
+			if s.passes.Len() > 0 {
+				s.passes.Publish([]types.StatsJSON{})
+			}
 			continue
 		}
 
@@ -115,6 +198,7 @@ func (s *Collector) Run() {
 			continue
 		}
 
+		var pass []types.StatsJSON
 		for _, pair := range pairs {
 			stats, err := s.supervisor.GetContainerStats(pair.container)
 
@@ -132,23 +216,30 @@ func (s *Collector) Run() {
 				stats.CPUStats.SystemUsage = systemUsage
 				stats.CPUStats.OnlineCPUs = onlineCPUs
 
-				pair.publisher.Publish(*stats)
-
 			case notRunningErr, notFoundErr:
 				// publish empty stats containing only name and ID if not running or not found
-				pair.publisher.Publish(types.StatsJSON{
+				stats = &types.StatsJSON{
 					Name: pair.container.Name,
 					ID:   pair.container.ID,
-				})
+				}
 
 			default:
 				logrus.Errorf("collecting stats for %s: %v", pair.container.ID, err)
-				pair.publisher.Publish(types.StatsJSON{
+				stats = &types.StatsJSON{
 					Name: pair.container.Name,
 					ID:   pair.container.ID,
-				})
+				}
+			}
+
+			if pair.publisher != nil {
+				pair.publisher.Publish(*stats)
+			}
+			s.observe(pair.container, *stats)
+			if !stats.Read.IsZero() {
+				pass = append(pass, *stats)
 			}
 		}
+		s.passes.Publish(pass)
 	}
 }
 
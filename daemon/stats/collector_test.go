@@ -0,0 +1,109 @@
+package stats // import "github.com/docker/docker/daemon/stats"
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
+)
+
+type fakeSupervisor struct{}
+
+func (fakeSupervisor) GetContainerStats(c *container.Container) (*types.StatsJSON, error) {
+	return &types.StatsJSON{Name: c.Name, ID: c.ID, Stats: types.Stats{Read: time.Now()}}, nil
+}
+
+type fakeObserver struct {
+	mu       sync.Mutex
+	observed map[string]int
+	forgot   []string
+}
+
+func (o *fakeObserver) Observe(c *container.Container, stats types.StatsJSON) {
+	o.mu.Lock()
+	o.observed[stats.ID]++
+	o.mu.Unlock()
+}
+
+func (o *fakeObserver) Forget(c *container.Container) {
+	o.mu.Lock()
+	o.forgot = append(o.forgot, c.ID)
+	o.mu.Unlock()
+}
+
+func TestCollectorTrack(t *testing.T) {
+	s := NewCollector(fakeSupervisor{}, 10*time.Millisecond)
+	o := &fakeObserver{observed: make(map[string]int)}
+	s.AddObserver(o)
+	go s.Run()
+
+	tracked := &container.Container{ID: "tracked"}
+	subscribed := &container.Container{ID: "subscribed"}
+	s.Track(tracked)
+	ch := s.Collect(subscribed)
+	defer s.Unsubscribe(subscribed, ch)
+
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		if o.observed["tracked"] < 2 {
+			return poll.Continue("observed %d samples", o.observed["tracked"])
+		}
+		return poll.Success()
+	}, poll.WithDelay(10*time.Millisecond), poll.WithTimeout(10*time.Second))
+
+	s.Untrack(tracked)
+	o.mu.Lock()
+	observed := o.observed["tracked"]
+	o.mu.Unlock()
+
+	// samples are only passed to the observers for the tracked containers
+	select {
+	case <-ch:
+	case <-time.After(10 * time.Second):
+		t.Fatal("no sample published to the subscriber")
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	assert.Check(t, is.Equal(o.observed["tracked"], observed))
+	assert.Check(t, is.Equal(o.observed["subscribed"], 0))
+	assert.Check(t, is.DeepEqual(o.forgot, []string{"tracked"}))
+}
+
+func TestCollectorCollectAll(t *testing.T) {
+	s := NewCollector(fakeSupervisor{}, 10*time.Millisecond)
+	go s.Run()
+
+	ch := s.CollectAll()
+	defer s.UnsubscribeAll(ch)
+
+	// passes are published when no container is sampled
+	select {
+	case v := <-ch:
+		assert.Check(t, is.Len(v.([]types.StatsJSON), 0))
+	case <-time.After(10 * time.Second):
+		t.Fatal("no pass published")
+	}
+
+	s.Track(&container.Container{ID: "a"})
+	s.Track(&container.Container{ID: "b"})
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		select {
+		case v := <-ch:
+			pass := v.([]types.StatsJSON)
+			if len(pass) != 2 {
+				return poll.Continue("pass has %d samples", len(pass))
+			}
+			return poll.Success()
+		case <-time.After(time.Second):
+			return poll.Continue("no pass published")
+		}
+	}, poll.WithDelay(10*time.Millisecond), poll.WithTimeout(10*time.Second))
+}
@@ -0,0 +1,195 @@
+package stats // import "github.com/docker/docker/daemon/stats"
+
+import (
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+)
+
+// HistoryTier is a level of retention of the stats history. The samples are
+// kept at Resolution for Retention.
+type HistoryTier struct {
+	Resolution time.Duration
+	Retention  time.Duration
+}
+
+// DefaultHistoryTiers keeps one sample per second for 5 minutes, and one
+// sample every 10 seconds for an hour.
+var DefaultHistoryTiers = []HistoryTier{
+	{Resolution: time.Second, Retention: 5 * time.Minute},
+	{Resolution: 10 * time.Second, Retention: time.Hour},
+}
+
+// History keeps the recent stats samples of the containers tracked by a
+// Collector, downsampled in bounded rings. The history of a container is kept
+// after it stops, until it's removed.
+type History struct {
+	tiers []HistoryTier
+
+	mu         sync.Mutex
+	containers map[string][]*sampleRing
+}
+
+// NewHistory creates a History with the given tiers, ordered from the finest
+// resolution to the coarsest.
+func NewHistory(tiers []HistoryTier) *History {
+	return &History{
+		tiers:      tiers,
+		containers: make(map[string][]*sampleRing),
+	}
+}
+
+// Observe adds a stats sample of c to its history. The empty samples of the
+// containers which are not running are ignored.
+func (h *History) Observe(c *container.Container, stats types.StatsJSON) {
+	if stats.Read.IsZero() {
+		return
+	}
+	// the previous read is filled in when querying the history
+	stats.PreCPUStats = types.CPUStats{}
+	stats.PreRead = time.Time{}
+	stats.CPUThrottling = nil
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	rings, ok := h.containers[c.ID]
+	if !ok {
+		for _, t := range h.tiers {
+			rings = append(rings, newSampleRing(t))
+		}
+		h.containers[c.ID] = rings
+	}
+	for _, r := range rings {
+		r.add(stats)
+	}
+}
+
+// Forget is a no-op: the history of the containers is kept when they stop.
+// It's removed with Remove.
+func (h *History) Forget(c *container.Container) {}
+
+// Remove removes the history of the container with the given ID.
+func (h *History) Remove(id string) {
+	h.mu.Lock()
+	delete(h.containers, id)
+	h.mu.Unlock()
+}
+
+// Query returns the samples of the container with the given ID read between
+// since and until, at most one per resolution. A zero until means now, and a
+// zero resolution means the finest resolution for which the history covers
+// since. The PreCPUStats of each sample are the CPUStats of the previous one,
+// and its CPUThrottling is computed since the previous one.
+func (h *History) Query(id string, since, until time.Time, resolution time.Duration) []types.StatsJSON {
+	h.mu.Lock()
+	rings, ok := h.containers[id]
+	if !ok {
+		h.mu.Unlock()
+		return nil
+	}
+	r := h.selectRing(rings, since, resolution)
+	samples := r.samplesBetween(since, until)
+	h.mu.Unlock()
+
+	if resolution < r.tier.Resolution {
+		resolution = r.tier.Resolution
+	}
+
+	var (
+		result []types.StatsJSON
+		last   time.Time
+	)
+	for _, s := range samples {
+		bucket := s.Read.Truncate(resolution)
+		if len(result) > 0 {
+			if !bucket.After(last) {
+				continue
+			}
+			prev := result[len(result)-1]
+			s.PreCPUStats = prev.CPUStats
+			s.PreRead = prev.Read
+		}
+		SetCPUThrottling(&s)
+		last = bucket
+		result = append(result, s)
+	}
+	return result
+}
+
+// selectRing returns the ring with the finest resolution which is not finer
+// than resolution, and which covers since if possible.
+func (h *History) selectRing(rings []*sampleRing, since time.Time, resolution time.Duration) *sampleRing {
+	selected := rings[0]
+	for _, r := range rings[1:] {
+		if resolution != 0 && r.tier.Resolution > resolution {
+			break
+		}
+		if selected.covers(since) {
+			break
+		}
+		selected = r
+	}
+	return selected
+}
+
+// sampleRing keeps the samples of a container at the resolution of a tier.
+type sampleRing struct {
+	tier    HistoryTier
+	samples []types.StatsJSON
+	start   int
+	len     int
+}
+
+func newSampleRing(tier HistoryTier) *sampleRing {
+	size := int(tier.Retention / tier.Resolution)
+	if size < 1 {
+		size = 1
+	}
+	return &sampleRing{tier: tier, samples: make([]types.StatsJSON, size)}
+}
+
+func (r *sampleRing) at(i int) *types.StatsJSON {
+	return &r.samples[(r.start+i)%len(r.samples)]
+}
+
+// add keeps s if it's the first sample in its resolution interval.
+func (r *sampleRing) add(s types.StatsJSON) {
+	if r.len > 0 {
+		last := r.at(r.len - 1).Read
+		if !s.Read.Truncate(r.tier.Resolution).After(last.Truncate(r.tier.Resolution)) {
+			return
+		}
+	}
+	if r.len == len(r.samples) {
+		r.samples[r.start] = s
+		r.start = (r.start + 1) % len(r.samples)
+		return
+	}
+	*r.at(r.len) = s
+	r.len++
+}
+
+// covers returns whether the ring holds the samples since t.
+func (r *sampleRing) covers(t time.Time) bool {
+	if r.len == 0 {
+		return true
+	}
+	return r.len < len(r.samples) || !r.at(0).Read.After(t)
+}
+
+func (r *sampleRing) samplesBetween(since, until time.Time) []types.StatsJSON {
+	var samples []types.StatsJSON
+	for i := 0; i < r.len; i++ {
+		s := r.at(i)
+		if s.Read.Before(since) {
+			continue
+		}
+		if !until.IsZero() && s.Read.After(until) {
+			break
+		}
+		samples = append(samples, *s)
+	}
+	return samples
+}
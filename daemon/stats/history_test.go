@@ -0,0 +1,76 @@
+package stats // import "github.com/docker/docker/daemon/stats"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func sampleAt(t time.Time, usage uint64) types.StatsJSON {
+	s := types.StatsJSON{}
+	s.Read = t
+	s.CPUStats.CPUUsage.TotalUsage = usage
+	return s
+}
+
+func readTimes(samples []types.StatsJSON) []time.Time {
+	var times []time.Time
+	for _, s := range samples {
+		times = append(times, s.Read)
+	}
+	return times
+}
+
+func TestHistory(t *testing.T) {
+	h := NewHistory([]HistoryTier{
+		{Resolution: time.Second, Retention: 10 * time.Second},
+		{Resolution: 5 * time.Second, Retention: time.Minute},
+	})
+	c := &container.Container{ID: "c1"}
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time {
+		return start.Add(time.Duration(seconds) * time.Second)
+	}
+	for i := 0; i < 30; i++ {
+		h.Observe(c, sampleAt(at(i), uint64(i)))
+	}
+	// samples of stopped containers, and samples in the same interval, are
+	// not kept
+	h.Observe(c, types.StatsJSON{ID: c.ID})
+	h.Observe(c, sampleAt(at(29).Add(100*time.Millisecond), 0))
+
+	// the finest tier covers the last 10 seconds
+	samples := h.Query(c.ID, at(25), time.Time{}, 0)
+	assert.Check(t, is.DeepEqual(readTimes(samples), []time.Time{at(25), at(26), at(27), at(28), at(29)}))
+	assert.Check(t, samples[0].PreRead.IsZero())
+	assert.Check(t, is.Equal(samples[1].PreRead, at(25)))
+	assert.Check(t, is.Equal(samples[1].PreCPUStats.CPUUsage.TotalUsage, uint64(25)))
+
+	samples = h.Query(c.ID, at(25), at(27), 0)
+	assert.Check(t, is.DeepEqual(readTimes(samples), []time.Time{at(25), at(26), at(27)}))
+
+	// older samples come from the coarser tier
+	samples = h.Query(c.ID, at(5), at(20), 0)
+	assert.Check(t, is.DeepEqual(readTimes(samples), []time.Time{at(5), at(10), at(15), at(20)}))
+
+	// samples are downsampled to the requested resolution
+	samples = h.Query(c.ID, at(20), time.Time{}, 2*time.Second)
+	assert.Check(t, is.DeepEqual(readTimes(samples), []time.Time{at(20), at(22), at(24), at(26), at(28)}))
+	assert.Check(t, is.Equal(samples[1].PreCPUStats.CPUUsage.TotalUsage, uint64(20)))
+
+	samples = h.Query(c.ID, at(20), time.Time{}, 10*time.Second)
+	assert.Check(t, is.DeepEqual(readTimes(samples), []time.Time{at(20)}))
+
+	assert.Check(t, is.Len(h.Query("unknown", time.Time{}, time.Time{}, 0), 0))
+
+	// the history is kept when the container stops
+	h.Forget(c)
+	assert.Check(t, is.Len(h.Query(c.ID, at(25), time.Time{}, 0), 5))
+	h.Remove(c.ID)
+	assert.Check(t, is.Len(h.Query(c.ID, time.Time{}, time.Time{}, 0), 0))
+}
@@ -0,0 +1,194 @@
+package stats // import "github.com/docker/docker/daemon/stats"
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+	"github.com/docker/go-metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// containerLabelPrefix is the prefix of the metric labels holding the labels
+// of the containers.
+const containerLabelPrefix = "container_label_"
+
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// metricLabelName returns the name of the metric label holding the value of
+// the container label key.
+func metricLabelName(key string) string {
+	return containerLabelPrefix + invalidLabelChars.ReplaceAllString(key, "_")
+}
+
+// ContainerMetrics exports the resource usage of the containers tracked by a
+// Collector as prometheus metrics. The metrics are labelled by the ID, name
+// and image of the containers, and by the container labels of an allowlist,
+// to keep their cardinality under control.
+type ContainerMetrics struct {
+	labels []string
+
+	cpuUsage, cpuUser, cpuSystem       *prometheus.Desc
+	cpuThrottledPeriods, cpuThrottled  *prometheus.Desc
+	memoryUsage, memoryLimit           *prometheus.Desc
+	networkRxBytes, networkTxBytes     *prometheus.Desc
+	networkRxPackets, networkTxPackets *prometheus.Desc
+	networkRxErrors, networkTxErrors   *prometheus.Desc
+	networkRxDropped, networkTxDropped *prometheus.Desc
+	blkioReadBytes, blkioWriteBytes    *prometheus.Desc
+	pidsCurrent, pidsLimit             *prometheus.Desc
+
+	mu      sync.Mutex
+	samples map[string]containerSample
+}
+
+type containerSample struct {
+	labelValues []string
+	stats       types.StatsJSON
+}
+
+// NewContainerMetrics creates the container metrics in namespace ns. The
+// container labels in labels are added to the labels of the metrics.
+func NewContainerMetrics(ns *metrics.Namespace, labels []string) (*ContainerMetrics, error) {
+	labelNames := []string{"id", "name", "image"}
+	seen := make(map[string]string)
+	for _, l := range labels {
+		name := metricLabelName(l)
+		if other, ok := seen[name]; ok {
+			return nil, fmt.Errorf("container labels %q and %q map to the same metric label %s", other, l, name)
+		}
+		seen[name] = l
+		labelNames = append(labelNames, name)
+	}
+	netLabelNames := append(append([]string(nil), labelNames...), "interface")
+
+	return &ContainerMetrics{
+		labels: labels,
+
+		cpuUsage:            ns.NewDesc("container_cpu_usage_seconds", "The total CPU time consumed by the container", metrics.Total, labelNames...),
+		cpuUser:             ns.NewDesc("container_cpu_user_seconds", "The CPU time consumed by the container in user mode", metrics.Total, labelNames...),
+		cpuSystem:           ns.NewDesc("container_cpu_system_seconds", "The CPU time consumed by the container in kernel mode", metrics.Total, labelNames...),
+		cpuThrottledPeriods: ns.NewDesc("container_cpu_throttled_periods", "The number of periods the container was throttled", metrics.Total, labelNames...),
+		cpuThrottled:        ns.NewDesc("container_cpu_throttled_seconds", "The time the container was throttled", metrics.Total, labelNames...),
+		memoryUsage:         ns.NewDesc("container_memory_usage", "The memory usage of the container", metrics.Bytes, labelNames...),
+		memoryLimit:         ns.NewDesc("container_memory_limit", "The memory limit of the container", metrics.Bytes, labelNames...),
+		networkRxBytes:      ns.NewDesc("container_network_receive_bytes", "The number of bytes received by the container", metrics.Total, netLabelNames...),
+		networkTxBytes:      ns.NewDesc("container_network_transmit_bytes", "The number of bytes sent by the container", metrics.Total, netLabelNames...),
+		networkRxPackets:    ns.NewDesc("container_network_receive_packets", "The number of packets received by the container", metrics.Total, netLabelNames...),
+		networkTxPackets:    ns.NewDesc("container_network_transmit_packets", "The number of packets sent by the container", metrics.Total, netLabelNames...),
+		networkRxErrors:     ns.NewDesc("container_network_receive_errors", "The number of errors while receiving", metrics.Total, netLabelNames...),
+		networkTxErrors:     ns.NewDesc("container_network_transmit_errors", "The number of errors while sending", metrics.Total, netLabelNames...),
+		networkRxDropped:    ns.NewDesc("container_network_receive_dropped", "The number of incoming packets dropped", metrics.Total, netLabelNames...),
+		networkTxDropped:    ns.NewDesc("container_network_transmit_dropped", "The number of outgoing packets dropped", metrics.Total, netLabelNames...),
+		blkioReadBytes:      ns.NewDesc("container_blkio_read_bytes", "The number of bytes read from block devices by the container", metrics.Total, labelNames...),
+		blkioWriteBytes:     ns.NewDesc("container_blkio_write_bytes", "The number of bytes written to block devices by the container", metrics.Total, labelNames...),
+		pidsCurrent:         ns.NewDesc("container_pids", "The number of processes of the container", metrics.Unit("current"), labelNames...),
+		pidsLimit:           ns.NewDesc("container_pids", "The maximum number of processes of the container", metrics.Unit("limit"), labelNames...),
+
+		samples: make(map[string]containerSample),
+	}, nil
+}
+
+// Observe stores the last stats sample of c. The samples of containers which
+// are not running are discarded.
+func (m *ContainerMetrics) Observe(c *container.Container, stats types.StatsJSON) {
+	if stats.Read.IsZero() {
+		m.Forget(c)
+		return
+	}
+
+	values := []string{c.ID, strings.TrimPrefix(c.Name, "/"), c.Config.Image}
+	for _, l := range m.labels {
+		values = append(values, c.Config.Labels[l])
+	}
+
+	m.mu.Lock()
+	m.samples[c.ID] = containerSample{labelValues: values, stats: stats}
+	m.mu.Unlock()
+}
+
+// Forget removes the metrics of c.
+func (m *ContainerMetrics) Forget(c *container.Container) {
+	m.mu.Lock()
+	delete(m.samples, c.ID)
+	m.mu.Unlock()
+}
+
+// Describe implements prometheus.Collector.
+func (m *ContainerMetrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		m.cpuUsage, m.cpuUser, m.cpuSystem, m.cpuThrottledPeriods, m.cpuThrottled,
+		m.memoryUsage, m.memoryLimit,
+		m.networkRxBytes, m.networkTxBytes, m.networkRxPackets, m.networkTxPackets,
+		m.networkRxErrors, m.networkTxErrors, m.networkRxDropped, m.networkTxDropped,
+		m.blkioReadBytes, m.blkioWriteBytes,
+		m.pidsCurrent, m.pidsLimit,
+	} {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (m *ContainerMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.mu.Lock()
+	samples := make([]containerSample, 0, len(m.samples))
+	for _, s := range m.samples {
+		samples = append(samples, s)
+	}
+	m.mu.Unlock()
+
+	for _, s := range samples {
+		m.collectSample(ch, s)
+	}
+}
+
+func (m *ContainerMetrics) collectSample(ch chan<- prometheus.Metric, s containerSample) {
+	counter := func(desc *prometheus.Desc, v float64, labelValues ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, v, labelValues...)
+	}
+	gauge := func(desc *prometheus.Desc, v float64, labelValues ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v, labelValues...)
+	}
+	stats := s.stats
+	lv := s.labelValues
+
+	cpu := stats.CPUStats
+	counter(m.cpuUsage, nanosecondsToSeconds(cpu.CPUUsage.TotalUsage), lv...)
+	counter(m.cpuUser, nanosecondsToSeconds(cpu.CPUUsage.UsageInUsermode), lv...)
+	counter(m.cpuSystem, nanosecondsToSeconds(cpu.CPUUsage.UsageInKernelmode), lv...)
+	counter(m.cpuThrottledPeriods, float64(cpu.ThrottlingData.ThrottledPeriods), lv...)
+	counter(m.cpuThrottled, nanosecondsToSeconds(cpu.ThrottlingData.ThrottledTime), lv...)
+
+	gauge(m.memoryUsage, float64(stats.MemoryStats.Usage), lv...)
+	if stats.MemoryStats.Limit != 0 {
+		gauge(m.memoryLimit, float64(stats.MemoryStats.Limit), lv...)
+	}
+
+	for iface, n := range stats.Networks {
+		netLv := append(append([]string(nil), lv...), iface)
+		counter(m.networkRxBytes, float64(n.RxBytes), netLv...)
+		counter(m.networkTxBytes, float64(n.TxBytes), netLv...)
+		counter(m.networkRxPackets, float64(n.RxPackets), netLv...)
+		counter(m.networkTxPackets, float64(n.TxPackets), netLv...)
+		counter(m.networkRxErrors, float64(n.RxErrors), netLv...)
+		counter(m.networkTxErrors, float64(n.TxErrors), netLv...)
+		counter(m.networkRxDropped, float64(n.RxDropped), netLv...)
+		counter(m.networkTxDropped, float64(n.TxDropped), netLv...)
+	}
+
+	read, write := blkioBytes(stats.BlkioStats)
+	counter(m.blkioReadBytes, float64(read), lv...)
+	counter(m.blkioWriteBytes, float64(write), lv...)
+
+	gauge(m.pidsCurrent, float64(stats.PidsStats.Current), lv...)
+	if stats.PidsStats.Limit != 0 {
+		gauge(m.pidsLimit, float64(stats.PidsStats.Limit), lv...)
+	}
+}
+
+func nanosecondsToSeconds(ns uint64) float64 {
+	return float64(ns) / 1e9
+}
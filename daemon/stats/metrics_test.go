@@ -0,0 +1,118 @@
+package stats // import "github.com/docker/docker/daemon/stats"
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/go-metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+// collectMetrics returns the values of the metrics collected from m, by
+// metric name, and the labels of the metrics.
+func collectMetrics(t *testing.T, m prometheus.Collector) (map[string][]float64, []map[string]string) {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		m.Collect(ch)
+		close(ch)
+	}()
+
+	values := make(map[string][]float64)
+	var labels []map[string]string
+	for metric := range ch {
+		var pb dto.Metric
+		assert.NilError(t, metric.Write(&pb))
+		desc := metric.Desc().String()
+		name := desc[strings.Index(desc, `"`)+1:]
+		name = name[:strings.Index(name, `"`)]
+
+		var v float64
+		switch {
+		case pb.Counter != nil:
+			v = pb.Counter.GetValue()
+		case pb.Gauge != nil:
+			v = pb.Gauge.GetValue()
+		}
+		values[name] = append(values[name], v)
+
+		l := make(map[string]string)
+		for _, lp := range pb.Label {
+			l[lp.GetName()] = lp.GetValue()
+		}
+		labels = append(labels, l)
+	}
+	return values, labels
+}
+
+func TestContainerMetrics(t *testing.T) {
+	ns := metrics.NewNamespace("engine", "daemon", nil)
+	m, err := NewContainerMetrics(ns, []string{"com.example.team", "missing"})
+	assert.NilError(t, err)
+
+	c := &container.Container{
+		ID:   "a7317399f3f8",
+		Name: "/web",
+		Config: &containertypes.Config{
+			Image:  "nginx:latest",
+			Labels: map[string]string{"com.example.team": "frontend", "other": "ignored"},
+		},
+	}
+	stats := types.StatsJSON{}
+	stats.Read = time.Now()
+	stats.CPUStats.CPUUsage.TotalUsage = 2500000000
+	stats.MemoryStats.Usage = 1024
+	stats.MemoryStats.Limit = 4096
+	stats.PidsStats.Current = 3
+	stats.BlkioStats.IoServiceBytesRecursive = []types.BlkioStatEntry{
+		{Op: "Read", Value: 10},
+		{Op: "read", Value: 5},
+		{Op: "Write", Value: 7},
+		{Op: "Total", Value: 22},
+	}
+	stats.Networks = map[string]types.NetworkStats{"eth0": {RxBytes: 100, TxBytes: 50}}
+	m.Observe(c, stats)
+
+	values, labels := collectMetrics(t, m)
+	assert.Check(t, is.DeepEqual(values["engine_daemon_container_cpu_usage_seconds_total"], []float64{2.5}))
+	assert.Check(t, is.DeepEqual(values["engine_daemon_container_memory_usage_bytes"], []float64{1024}))
+	assert.Check(t, is.DeepEqual(values["engine_daemon_container_memory_limit_bytes"], []float64{4096}))
+	assert.Check(t, is.DeepEqual(values["engine_daemon_container_blkio_read_bytes_total"], []float64{15}))
+	assert.Check(t, is.DeepEqual(values["engine_daemon_container_blkio_write_bytes_total"], []float64{7}))
+	assert.Check(t, is.DeepEqual(values["engine_daemon_container_network_receive_bytes_total"], []float64{100}))
+	assert.Check(t, is.DeepEqual(values["engine_daemon_container_pids_current"], []float64{3}))
+	// unset limits are not exported
+	assert.Check(t, is.Len(values["engine_daemon_container_pids_limit"], 0))
+
+	for _, l := range labels {
+		assert.Check(t, is.Equal(l["id"], "a7317399f3f8"))
+		assert.Check(t, is.Equal(l["name"], "web"))
+		assert.Check(t, is.Equal(l["image"], "nginx:latest"))
+		assert.Check(t, is.Equal(l["container_label_com_example_team"], "frontend"))
+		assert.Check(t, is.Equal(l["container_label_missing"], ""))
+		_, ok := l["container_label_other"]
+		assert.Check(t, !ok)
+	}
+
+	// stats of stopped containers only have their name and ID set
+	m.Observe(c, types.StatsJSON{Name: c.Name, ID: c.ID})
+	values, _ = collectMetrics(t, m)
+	assert.Check(t, is.Len(values, 0))
+
+	m.Observe(c, stats)
+	m.Forget(c)
+	values, _ = collectMetrics(t, m)
+	assert.Check(t, is.Len(values, 0))
+}
+
+func TestContainerMetricsLabelConflict(t *testing.T) {
+	ns := metrics.NewNamespace("engine", "daemon", nil)
+	_, err := NewContainerMetrics(ns, []string{"com.example.team", "com_example_team"})
+	assert.Check(t, is.ErrorContains(err, "map to the same metric label"))
+}
@@ -0,0 +1,66 @@
+package stats // import "github.com/docker/docker/daemon/stats"
+
+import (
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// Totals aggregates the stats of the containers sampled in a collection pass.
+// The CPU percentage is computed from the PreCPUStats of the samples. The
+// memory limit is left to the caller.
+func Totals(samples []types.StatsJSON) types.SystemStatsTotals {
+	t := types.SystemStatsTotals{Containers: len(samples)}
+	for _, s := range samples {
+		t.CPUUsage += s.CPUStats.CPUUsage.TotalUsage
+		t.CPUPercent += cpuPercent(s)
+		// the samples are taken close to each other in a pass
+		if s.CPUStats.SystemUsage > t.SystemUsage {
+			t.SystemUsage = s.CPUStats.SystemUsage
+		}
+		if s.CPUStats.OnlineCPUs > t.OnlineCPUs {
+			t.OnlineCPUs = s.CPUStats.OnlineCPUs
+		}
+		t.MemoryUsage += s.MemoryStats.Usage
+		for _, n := range s.Networks {
+			t.NetworkRxBytes += n.RxBytes
+			t.NetworkTxBytes += n.TxBytes
+		}
+		read, write := blkioBytes(s.BlkioStats)
+		t.BlkioReadBytes += read
+		t.BlkioWriteBytes += write
+		t.Pids += s.PidsStats.Current
+	}
+	return t
+}
+
+// cpuPercent returns the CPU usage of s since its previous read, in percent
+// of one CPU, like the docker CLI computes it on Linux.
+func cpuPercent(s types.StatsJSON) float64 {
+	cur, pre := s.CPUStats, s.PreCPUStats
+	if s.PreRead.IsZero() || cur.CPUUsage.TotalUsage < pre.CPUUsage.TotalUsage || cur.SystemUsage <= pre.SystemUsage {
+		return 0
+	}
+	onlineCPUs := cur.OnlineCPUs
+	if onlineCPUs == 0 {
+		onlineCPUs = uint32(len(cur.CPUUsage.PercpuUsage))
+	}
+	cpuDelta := float64(cur.CPUUsage.TotalUsage - pre.CPUUsage.TotalUsage)
+	systemDelta := float64(cur.SystemUsage - pre.SystemUsage)
+	return cpuDelta / systemDelta * float64(onlineCPUs) * 100
+}
+
+// blkioBytes returns the number of bytes read from and written to block
+// devices.
+func blkioBytes(s types.BlkioStats) (read, write uint64) {
+	for _, e := range s.IoServiceBytesRecursive {
+		// the operations are capitalized with cgroup v1 only
+		switch strings.ToLower(e.Op) {
+		case "read":
+			read += e.Value
+		case "write":
+			write += e.Value
+		}
+	}
+	return read, write
+}
@@ -0,0 +1,52 @@
+package stats // import "github.com/docker/docker/daemon/stats"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestTotals(t *testing.T) {
+	now := time.Now()
+	a := types.StatsJSON{ID: "a"}
+	a.Read = now
+	a.PreRead = now.Add(-time.Second)
+	a.CPUStats.CPUUsage.TotalUsage = 3000
+	a.CPUStats.SystemUsage = 20000
+	a.CPUStats.OnlineCPUs = 4
+	a.PreCPUStats.CPUUsage.TotalUsage = 1000
+	a.PreCPUStats.SystemUsage = 10000
+	a.MemoryStats.Usage = 100
+	a.PidsStats.Current = 2
+	a.Networks = map[string]types.NetworkStats{"eth0": {RxBytes: 10, TxBytes: 5}, "eth1": {RxBytes: 1}}
+	a.BlkioStats.IoServiceBytesRecursive = []types.BlkioStatEntry{{Op: "Read", Value: 7}, {Op: "Write", Value: 3}}
+
+	// no previous read
+	b := types.StatsJSON{ID: "b"}
+	b.Read = now
+	b.CPUStats.CPUUsage.TotalUsage = 500
+	b.CPUStats.SystemUsage = 20010
+	b.CPUStats.OnlineCPUs = 4
+	b.MemoryStats.Usage = 50
+	b.PidsStats.Current = 1
+	b.BlkioStats.IoServiceBytesRecursive = []types.BlkioStatEntry{{Op: "read", Value: 1}}
+
+	assert.Check(t, is.DeepEqual(Totals([]types.StatsJSON{a, b}), types.SystemStatsTotals{
+		Containers:      2,
+		CPUUsage:        3500,
+		CPUPercent:      80,
+		SystemUsage:     20010,
+		OnlineCPUs:      4,
+		MemoryUsage:     150,
+		NetworkRxBytes:  11,
+		NetworkTxBytes:  5,
+		BlkioReadBytes:  8,
+		BlkioWriteBytes: 3,
+		Pids:            3,
+	}))
+
+	assert.Check(t, is.DeepEqual(Totals(nil), types.SystemStatsTotals{}))
+}
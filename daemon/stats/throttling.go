@@ -0,0 +1,23 @@
+package stats // import "github.com/docker/docker/daemon/stats"
+
+import "github.com/docker/docker/api/types"
+
+// SetCPUThrottling sets the CPU throttling of s between its previous read
+// and its read, from the throttling data of its CPUStats and PreCPUStats. It's
+// left unset when the counters were reset, after a restart of the container.
+func SetCPUThrottling(s *types.StatsJSON) {
+	s.CPUThrottling = nil
+	cur, pre := s.CPUStats.ThrottlingData, s.PreCPUStats.ThrottlingData
+	if s.PreRead.IsZero() || cur.Periods < pre.Periods || cur.ThrottledPeriods < pre.ThrottledPeriods || cur.ThrottledTime < pre.ThrottledTime {
+		return
+	}
+	t := &types.CPUThrottling{
+		Periods:          cur.Periods - pre.Periods,
+		ThrottledPeriods: cur.ThrottledPeriods - pre.ThrottledPeriods,
+		ThrottledTime:    cur.ThrottledTime - pre.ThrottledTime,
+	}
+	if t.Periods > 0 {
+		t.ThrottledPercent = float64(t.ThrottledPeriods) / float64(t.Periods) * 100
+	}
+	s.CPUThrottling = t
+}
@@ -0,0 +1,35 @@
+package stats // import "github.com/docker/docker/daemon/stats"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestSetCPUThrottling(t *testing.T) {
+	s := types.StatsJSON{}
+	s.Read = time.Now()
+	s.CPUStats.ThrottlingData = types.ThrottlingData{Periods: 110, ThrottledPeriods: 25, ThrottledTime: 3000}
+
+	// no previous read
+	SetCPUThrottling(&s)
+	assert.Check(t, is.Nil(s.CPUThrottling))
+
+	s.PreRead = s.Read.Add(-time.Second)
+	s.PreCPUStats.ThrottlingData = types.ThrottlingData{Periods: 10, ThrottledPeriods: 5, ThrottledTime: 1000}
+	SetCPUThrottling(&s)
+	assert.Check(t, is.DeepEqual(s.CPUThrottling, &types.CPUThrottling{
+		Periods:          100,
+		ThrottledPeriods: 20,
+		ThrottledTime:    2000,
+		ThrottledPercent: 20,
+	}))
+
+	// the counters are reset when the container restarts
+	s.PreCPUStats.ThrottlingData.Periods = 200
+	SetCPUThrottling(&s)
+	assert.Check(t, is.Nil(s.CPUThrottling))
+}
@@ -0,0 +1,10 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+)
+
+func cgroupStats(c *container.Container, s *types.StatsJSON) error {
+	return nil
+}
@@ -0,0 +1,211 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+// isCgroup2UnifiedMode returns whether the cgroup v2 hierarchy is mounted
+// on its own on /sys/fs/cgroup.
+func isCgroup2UnifiedMode() bool {
+	var st unix.Statfs_t
+	if err := unix.Statfs(cgroupRoot, &st); err != nil {
+		return false
+	}
+	return st.Type == unix.CGROUP2_SUPER_MAGIC
+}
+
+// cgroupStats adds the pressure stall information and the memory events of
+// the cgroup of c, which containerd doesn't report, to s. The pressure stall
+// information and the memory events other than OOM kills are only available
+// with cgroup v2.
+func cgroupStats(c *container.Container, s *types.StatsJSON) error {
+	pid := c.GetPID()
+	if pid == 0 {
+		return nil
+	}
+	paths, err := cgroups.ParseCgroupFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return err
+	}
+
+	if isCgroup2UnifiedMode() {
+		dir := filepath.Join(cgroupRoot, paths[""])
+		var ps types.PressureStats
+		for _, r := range []struct {
+			file string
+			p    **types.Pressure
+		}{
+			{"cpu.pressure", &ps.CPU},
+			{"memory.pressure", &ps.Memory},
+			{"io.pressure", &ps.IO},
+		} {
+			p, err := readCgroupFile(dir, r.file, parsePressure)
+			if err != nil {
+				return err
+			}
+			if p != nil {
+				*r.p = p.(*types.Pressure)
+			}
+		}
+		if ps.CPU != nil || ps.Memory != nil || ps.IO != nil {
+			s.PressureStats = &ps
+		}
+		events, err := readCgroupFile(dir, "memory.events", parseMemoryEvents)
+		if err != nil {
+			return err
+		}
+		if events != nil {
+			s.MemoryStats.Events = events.(*types.MemoryEvents)
+		}
+		return nil
+	}
+
+	mnt, err := cgroups.FindCgroupMountpoint("", "memory")
+	if err != nil {
+		if cgroups.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	events, err := readCgroupFile(filepath.Join(mnt, paths["memory"]), "memory.oom_control", parseOOMControl)
+	if err != nil {
+		return err
+	}
+	if events != nil {
+		s.MemoryStats.Events = events.(*types.MemoryEvents)
+	}
+	return nil
+}
+
+// readCgroupFile parses the file name of the cgroup dir with parse. A missing
+// file, which is not supported by the kernel or the controllers enabled in
+// the cgroup, is ignored.
+func readCgroupFile(dir, name string, parse func(io.Reader) (interface{}, error)) (interface{}, error) {
+	f, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	v, err := parse(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing %s", f.Name())
+	}
+	return v, nil
+}
+
+// parsePressure parses the pressure stall information of a resource, in the
+// format of the *.pressure files:
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parsePressure(r io.Reader) (interface{}, error) {
+	var p types.Pressure
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var data *types.PressureData
+		switch fields[0] {
+		case "some":
+			data = &p.Some
+		case "full":
+			data = &p.Full
+		default:
+			continue
+		}
+		for _, f := range fields[1:] {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("invalid field %q", f)
+			}
+			var err error
+			switch kv[0] {
+			case "avg10":
+				data.Avg10, err = strconv.ParseFloat(kv[1], 64)
+			case "avg60":
+				data.Avg60, err = strconv.ParseFloat(kv[1], 64)
+			case "avg300":
+				data.Avg300, err = strconv.ParseFloat(kv[1], 64)
+			case "total":
+				data.Total, err = strconv.ParseUint(kv[1], 10, 64)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// parseKeyValues parses the flat keyed files of cgroups, made of one
+// "key value" pair per line.
+func parseKeyValues(r io.Reader) (map[string]uint64, error) {
+	values := make(map[string]uint64)
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid line %q", s.Text())
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[fields[0]] = v
+	}
+	return values, s.Err()
+}
+
+// parseMemoryEvents parses the memory.events file of cgroup v2.
+func parseMemoryEvents(r io.Reader) (interface{}, error) {
+	values, err := parseKeyValues(r)
+	if err != nil {
+		return nil, err
+	}
+	return &types.MemoryEvents{
+		Low:     values["low"],
+		High:    values["high"],
+		Max:     values["max"],
+		OOM:     values["oom"],
+		OOMKill: values["oom_kill"],
+	}, nil
+}
+
+// parseOOMControl parses the memory.oom_control file of cgroup v1, which
+// only reports the OOM kills since kernel 4.13.
+func parseOOMControl(r io.Reader) (interface{}, error) {
+	values, err := parseKeyValues(r)
+	if err != nil {
+		return nil, err
+	}
+	oomKill, ok := values["oom_kill"]
+	if !ok {
+		return nil, nil
+	}
+	return &types.MemoryEvents{OOMKill: oomKill}, nil
+}
@@ -0,0 +1,57 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestParsePressure(t *testing.T) {
+	p, err := parsePressure(strings.NewReader(`some avg10=1.50 avg60=0.25 avg300=0.00 total=12345
+full avg10=0.75 avg60=0.10 avg300=0.01 total=6789
+`))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(p, &types.Pressure{
+		Some: types.PressureData{Avg10: 1.5, Avg60: 0.25, Total: 12345},
+		Full: types.PressureData{Avg10: 0.75, Avg60: 0.1, Avg300: 0.01, Total: 6789},
+	}))
+
+	// cpu.pressure only reports "some" on kernels before 5.13
+	p, err = parsePressure(strings.NewReader("some avg10=0.00 avg60=0.00 avg300=0.00 total=42\n"))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(p, &types.Pressure{Some: types.PressureData{Total: 42}}))
+
+	_, err = parsePressure(strings.NewReader("some avg10\n"))
+	assert.Check(t, is.ErrorContains(err, "invalid field"))
+	_, err = parsePressure(strings.NewReader("some total=abc\n"))
+	assert.Check(t, is.ErrorContains(err, "invalid syntax"))
+}
+
+func TestParseMemoryEvents(t *testing.T) {
+	e, err := parseMemoryEvents(strings.NewReader(`low 1
+high 20
+max 3
+oom 2
+oom_kill 1
+oom_group_kill 0
+`))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(e, &types.MemoryEvents{Low: 1, High: 20, Max: 3, OOM: 2, OOMKill: 1}))
+
+	_, err = parseMemoryEvents(strings.NewReader("low\n"))
+	assert.Check(t, is.ErrorContains(err, "invalid line"))
+}
+
+func TestParseOOMControl(t *testing.T) {
+	e, err := parseOOMControl(strings.NewReader("oom_kill_disable 0\nunder_oom 0\noom_kill 4\n"))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(e, &types.MemoryEvents{OOMKill: 4}))
+
+	// oom_kill is only reported since kernel 4.13
+	e, err = parseOOMControl(strings.NewReader("oom_kill_disable 0\nunder_oom 0\n"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Nil(e))
+}
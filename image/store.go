@@ -24,6 +24,10 @@ type Store interface {
 	GetParent(id ID) (ID, error)
 	SetLastUpdated(id ID) error
 	GetLastUpdated(id ID) (time.Time, error)
+	SetLastUsed(id ID) error
+	GetLastUsed(id ID) (time.Time, error)
+	SetProtected(id ID, protected bool) error
+	IsProtected(id ID) bool
 	Children(id ID) []ID
 	Map() map[ID]*Image
 	Heads() map[ID]*Image
@@ -177,6 +181,11 @@ func (is *store) Create(config []byte) (ID, error) {
 		delete(is.images, imageID)
 		return "", err
 	}
+	// the images which were never used by a container are collected in the
+	// order they were created
+	if err := is.SetLastUsed(imageID); err != nil {
+		logrus.WithError(err).WithField("image", imageID).Warn("failed to set the last used time of the image")
+	}
 
 	return imageID, nil
 }
@@ -294,6 +303,36 @@ func (is *store) GetLastUpdated(id ID) (time.Time, error) {
 	return time.Parse(time.RFC3339Nano, string(bytes))
 }
 
+// SetLastUsed time for the image ID to the current time
+func (is *store) SetLastUsed(id ID) error {
+	lastUsed := []byte(time.Now().Format(time.RFC3339Nano))
+	return is.fs.SetMetadata(id.Digest(), "lastUsed", lastUsed)
+}
+
+// GetLastUsed time for the image ID
+func (is *store) GetLastUsed(id ID) (time.Time, error) {
+	bytes, err := is.fs.GetMetadata(id.Digest(), "lastUsed")
+	if err != nil || len(bytes) == 0 {
+		// No lastUsed time
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, string(bytes))
+}
+
+// SetProtected sets whether the image ID is protected from prune and removal
+func (is *store) SetProtected(id ID, protected bool) error {
+	if !protected {
+		return is.fs.DeleteMetadata(id.Digest(), "protected")
+	}
+	return is.fs.SetMetadata(id.Digest(), "protected", []byte("true"))
+}
+
+// IsProtected returns whether the image ID is protected from prune and removal
+func (is *store) IsProtected(id ID) bool {
+	bytes, err := is.fs.GetMetadata(id.Digest(), "protected")
+	return err == nil && string(bytes) == "true"
+}
+
 func (is *store) Children(id ID) []ID {
 	is.RLock()
 	defer is.RUnlock()
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/layer"
 	"github.com/opencontainers/go-digest"
@@ -172,6 +173,44 @@ func TestGetAndSetLastUpdated(t *testing.T) {
 	assert.Check(t, cmp.Equal(updated.IsZero(), false))
 }
 
+func TestGetAndSetLastUsed(t *testing.T) {
+	store, cleanup := defaultImageStore(t)
+	defer cleanup()
+
+	before := time.Now()
+	id, err := store.Create([]byte(`{"comment": "abc1", "rootfs": {"type": "layers"}}`))
+	assert.NilError(t, err)
+
+	// the last used time is initialized when the image is created
+	created, err := store.GetLastUsed(id)
+	assert.NilError(t, err)
+	assert.Check(t, !created.Before(before))
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Check(t, store.SetLastUsed(id))
+
+	used, err := store.GetLastUsed(id)
+	assert.NilError(t, err)
+	assert.Check(t, used.After(created))
+}
+
+func TestSetProtected(t *testing.T) {
+	store, cleanup := defaultImageStore(t)
+	defer cleanup()
+
+	id, err := store.Create([]byte(`{"comment": "abc1", "rootfs": {"type": "layers"}}`))
+	assert.NilError(t, err)
+	assert.Check(t, !store.IsProtected(id))
+
+	assert.NilError(t, store.SetProtected(id, true))
+	assert.Check(t, store.IsProtected(id))
+
+	assert.NilError(t, store.SetProtected(id, false))
+	assert.Check(t, !store.IsProtected(id))
+	// unprotecting an image which is not protected is a no-op
+	assert.NilError(t, store.SetProtected(id, false))
+}
+
 func TestStoreLen(t *testing.T) {
 	store, cleanup := defaultImageStore(t)
 	defer cleanup()
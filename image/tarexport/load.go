@@ -52,6 +52,9 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	manifestFile, err := os.Open(manifestPath)
 	if err != nil {
 		if os.IsNotExist(err) {
+			if isOCILayout(tmpDir) {
+				return l.ociLoad(tmpDir, outStream, progressOutput)
+			}
 			return l.legacyLoad(tmpDir, outStream, progressOutput)
 		}
 		return err
@@ -76,53 +79,15 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 		if err != nil {
 			return err
 		}
-		img, err := image.NewFromJSON(config)
-		if err != nil {
-			return err
-		}
-		if err := checkCompatibleOS(img.OS); err != nil {
-			return err
-		}
-		rootFS := *img.RootFS
-		rootFS.DiffIDs = nil
-
-		if expected, actual := len(m.Layers), len(img.RootFS.DiffIDs); expected != actual {
-			return fmt.Errorf("invalid manifest, layers length mismatch: expected %d, got %d", expected, actual)
-		}
-
-		// On Windows, validate the platform, defaulting to windows if not present.
-		os := img.OS
-		if os == "" {
-			os = runtime.GOOS
-		}
-		if runtime.GOOS == "windows" {
-			if (os != "windows") && (os != "linux") {
-				return fmt.Errorf("configuration for this image has an unsupported operating system: %s", os)
-			}
-		}
-
-		for i, diffID := range img.RootFS.DiffIDs {
-			layerPath, err := safePath(tmpDir, m.Layers[i])
+		var layerPaths []string
+		for _, p := range m.Layers {
+			layerPath, err := safePath(tmpDir, p)
 			if err != nil {
 				return err
 			}
-			r := rootFS
-			r.Append(diffID)
-			newLayer, err := l.lss[os].Get(r.ChainID())
-			if err != nil {
-				newLayer, err = l.loadLayer(layerPath, rootFS, diffID.String(), os, m.LayerSources[diffID], progressOutput)
-				if err != nil {
-					return err
-				}
-			}
-			defer layer.ReleaseAndLog(l.lss[os], newLayer)
-			if expected, actual := diffID, newLayer.DiffID(); expected != actual {
-				return fmt.Errorf("invalid diffID for layer %d: expected %q, got %q", i, expected, actual)
-			}
-			rootFS.Append(diffID)
+			layerPaths = append(layerPaths, layerPath)
 		}
-
-		imgID, err := l.is.Create(config)
+		imgID, err := l.loadImage(config, layerPaths, m.LayerSources, progressOutput)
 		if err != nil {
 			return err
 		}
@@ -162,6 +127,54 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	return nil
 }
 
+// loadImage registers the layers of the image with the given config, reading
+// them from layerPaths, and creates the image.
+func (l *tarexporter) loadImage(config []byte, layerPaths []string, layerSources map[layer.DiffID]distribution.Descriptor, progressOutput progress.Output) (image.ID, error) {
+	img, err := image.NewFromJSON(config)
+	if err != nil {
+		return "", err
+	}
+	if err := checkCompatibleOS(img.OS); err != nil {
+		return "", err
+	}
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+
+	if expected, actual := len(layerPaths), len(img.RootFS.DiffIDs); expected != actual {
+		return "", fmt.Errorf("invalid manifest, layers length mismatch: expected %d, got %d", expected, actual)
+	}
+
+	// On Windows, validate the platform, defaulting to windows if not present.
+	os := img.OS
+	if os == "" {
+		os = runtime.GOOS
+	}
+	if runtime.GOOS == "windows" {
+		if (os != "windows") && (os != "linux") {
+			return "", fmt.Errorf("configuration for this image has an unsupported operating system: %s", os)
+		}
+	}
+
+	for i, diffID := range img.RootFS.DiffIDs {
+		r := rootFS
+		r.Append(diffID)
+		newLayer, err := l.lss[os].Get(r.ChainID())
+		if err != nil {
+			newLayer, err = l.loadLayer(layerPaths[i], rootFS, diffID.String(), os, layerSources[diffID], progressOutput)
+			if err != nil {
+				return "", err
+			}
+		}
+		defer layer.ReleaseAndLog(l.lss[os], newLayer)
+		if expected, actual := diffID, newLayer.DiffID(); expected != actual {
+			return "", fmt.Errorf("invalid diffID for layer %d: expected %q, got %q", i, expected, actual)
+		}
+		rootFS.Append(diffID)
+	}
+
+	return l.is.Create(config)
+}
+
 func (l *tarexporter) setParentID(id, parentID image.ID) error {
 	img, err := l.is.Get(id)
 	if err != nil {
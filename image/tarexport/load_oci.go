@@ -0,0 +1,226 @@
+package tarexport // import "github.com/docker/docker/image/tarexport"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// isOCILayout returns whether dir holds an OCI image layout.
+func isOCILayout(dir string) bool {
+	p, err := safePath(dir, ocispec.ImageLayoutFile)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(p)
+	return err == nil
+}
+
+// ociLoad loads the images of the index of the OCI image layout in tmpDir.
+// Images are tagged with the reference in the io.containerd.image.name
+// annotation of their index descriptor or, if it's missing, with the
+// org.opencontainers.image.ref.name annotation when it holds a full
+// reference rather than only a tag.
+func (l *tarexporter) ociLoad(tmpDir string, outStream io.Writer, progressOutput progress.Output) error {
+	var layout ocispec.ImageLayout
+	if err := readJSONFile(tmpDir, ocispec.ImageLayoutFile, &layout); err != nil {
+		return err
+	}
+	if layout.Version != ocispec.ImageLayoutVersion {
+		return fmt.Errorf("unsupported OCI image layout version %q", layout.Version)
+	}
+	var index ocispec.Index
+	if err := readJSONFile(tmpDir, ociIndexFileName, &index); err != nil {
+		return err
+	}
+
+	loaded := make(map[digest.Digest]image.ID)
+	for _, desc := range index.Manifests {
+		manifestDesc, err := ociResolveManifest(tmpDir, desc)
+		if err != nil {
+			return err
+		}
+		imgID, ok := loaded[manifestDesc.Digest]
+		if !ok {
+			imgID, err = l.ociLoadImage(tmpDir, manifestDesc, progressOutput)
+			if err != nil {
+				return err
+			}
+			loaded[manifestDesc.Digest] = imgID
+			l.loggerImgEvent.LogImageEvent(imgID.String(), imgID.String(), "load")
+		}
+
+		ref := ociImageName(desc.Annotations)
+		if ref == nil {
+			fmt.Fprintf(outStream, "Loaded image ID: %s\n", imgID)
+			continue
+		}
+		l.setLoadedTag(ref, imgID.Digest(), outStream)
+		fmt.Fprintf(outStream, "Loaded image: %s\n", reference.FamiliarString(ref))
+	}
+	return nil
+}
+
+// ociResolveManifest returns the descriptor of the image manifest referenced
+// by desc, selecting the manifest of the platform of the daemon if desc is an
+// index.
+func ociResolveManifest(dir string, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageManifest, images.MediaTypeDockerSchema2Manifest:
+		return desc, nil
+	case ocispec.MediaTypeImageIndex, images.MediaTypeDockerSchema2ManifestList:
+		var index ocispec.Index
+		if err := readBlobJSON(dir, desc, &index); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		matcher := platforms.Default()
+		var candidates []ocispec.Descriptor
+		for _, m := range index.Manifests {
+			if m.Platform == nil || matcher.Match(*m.Platform) {
+				candidates = append(candidates, m)
+			}
+		}
+		if len(candidates) == 0 {
+			return ocispec.Descriptor{}, fmt.Errorf("no manifest for platform %s in index %s", platforms.DefaultString(), desc.Digest)
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if candidates[i].Platform == nil || candidates[j].Platform == nil {
+				return candidates[j].Platform == nil && candidates[i].Platform != nil
+			}
+			return matcher.Less(*candidates[i].Platform, *candidates[j].Platform)
+		})
+		return ociResolveManifest(dir, candidates[0])
+	default:
+		return ocispec.Descriptor{}, fmt.Errorf("unsupported media type %q for %s", desc.MediaType, desc.Digest)
+	}
+}
+
+// ociLoadImage loads the image of the manifest described by desc.
+func (l *tarexporter) ociLoadImage(dir string, desc ocispec.Descriptor, progressOutput progress.Output) (image.ID, error) {
+	var manifest ocispec.Manifest
+	if err := readBlobJSON(dir, desc, &manifest); err != nil {
+		return "", err
+	}
+	config, err := readBlob(dir, manifest.Config)
+	if err != nil {
+		return "", err
+	}
+
+	var layerPaths []string
+	var layerSources map[layer.DiffID]distribution.Descriptor
+	img, err := image.NewFromJSON(config)
+	if err != nil {
+		return "", err
+	}
+	for i, d := range manifest.Layers {
+		p, err := blobPath(dir, d.Digest)
+		if err != nil {
+			return "", err
+		}
+		layerPaths = append(layerPaths, p)
+		// keep the source of non-distributable layers, as for the layers of
+		// the docker format
+		if len(d.URLs) > 0 && i < len(img.RootFS.DiffIDs) {
+			if layerSources == nil {
+				layerSources = make(map[layer.DiffID]distribution.Descriptor)
+			}
+			layerSources[img.RootFS.DiffIDs[i]] = distribution.Descriptor{
+				MediaType: d.MediaType,
+				Digest:    d.Digest,
+				Size:      d.Size,
+				URLs:      d.URLs,
+			}
+		}
+	}
+	return l.loadImage(config, layerPaths, layerSources, progressOutput)
+}
+
+// ociImageName returns the reference to tag an image with from the
+// annotations of its index descriptor, or nil.
+func ociImageName(annotations map[string]string) reference.NamedTagged {
+	name := annotations[ociImageNameAnnotation]
+	if name == "" {
+		// the ref.name annotation is often only a tag, which can't name
+		// an image on its own
+		name = annotations[ocispec.AnnotationRefName]
+		if !strings.ContainsAny(name, ":/") {
+			return nil
+		}
+	}
+	named, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return nil
+	}
+	if tagged, ok := named.(reference.NamedTagged); ok {
+		// drop the digest of name:tag@digest references
+		ref, err := reference.WithTag(reference.TrimNamed(named), tagged.Tag())
+		if err != nil {
+			return nil
+		}
+		return ref
+	}
+	if _, ok := named.(reference.Canonical); ok {
+		return nil
+	}
+	return reference.TagNameOnly(named).(reference.NamedTagged)
+}
+
+func blobPath(dir string, dgst digest.Digest) (string, error) {
+	if err := dgst.Validate(); err != nil {
+		return "", err
+	}
+	return safePath(dir, filepath.Join(ociBlobsDir, string(dgst.Algorithm()), dgst.Hex()))
+}
+
+// readBlob reads the blob described by desc and verifies its digest.
+func readBlob(dir string, desc ocispec.Descriptor) ([]byte, error) {
+	p, err := blobPath(dir, desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	if actual := desc.Digest.Algorithm().FromBytes(data); actual != desc.Digest {
+		return nil, errors.Errorf("invalid digest for blob %s: got %s", desc.Digest, actual)
+	}
+	return data, nil
+}
+
+func readBlobJSON(dir string, desc ocispec.Descriptor, v interface{}) error {
+	data, err := readBlob(dir, desc)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(json.Unmarshal(data, v), "invalid blob %s", desc.Digest)
+}
+
+func readJSONFile(dir, name string, v interface{}) error {
+	p, err := safePath(dir, name)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return errors.Wrapf(json.NewDecoder(f).Decode(v), "invalid %s", name)
+}
@@ -45,6 +45,9 @@ func (l *tarexporter) Save(names []string, outStream io.Writer) error {
 
 	// Release all the image top layer references
 	defer l.releaseLayerReferences(images)
+	if l.format == FormatOCI {
+		return (&ociSaveSession{tarexporter: l, images: images}).save(outStream)
+	}
 	return (&saveSession{tarexporter: l, images: images}).save(outStream)
 }
 
@@ -0,0 +1,216 @@
+package tarexport // import "github.com/docker/docker/image/tarexport"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/system"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// ociSaveSession saves images in the OCI image layout. Every file of the
+// layout has the Unix epoch as modification time, so that saving the same
+// images twice gives the same archive.
+type ociSaveSession struct {
+	*tarexporter
+	outDir string
+	images map[image.ID]*imageDescriptor
+}
+
+func (s *ociSaveSession) save(outStream io.Writer) error {
+	tempDir, err := ioutil.TempDir("", "docker-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	s.outDir = tempDir
+	if err := os.MkdirAll(filepath.Join(tempDir, ociBlobsDir, string(digest.Canonical)), 0755); err != nil {
+		return err
+	}
+
+	ids := make([]image.ID, 0, len(s.images))
+	for id := range s.images {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	index := ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Manifests: []ocispec.Descriptor{},
+	}
+	for _, id := range ids {
+		desc, err := s.saveImage(id)
+		if err != nil {
+			return err
+		}
+
+		refs := s.images[id].refs
+		if len(refs) == 0 {
+			index.Manifests = append(index.Manifests, desc)
+		}
+		for _, ref := range refs {
+			d := desc
+			d.Annotations = map[string]string{
+				ociImageNameAnnotation:    ref.String(),
+				ocispec.AnnotationRefName: ref.Tag(),
+			}
+			index.Manifests = append(index.Manifests, d)
+		}
+		s.tarexporter.loggerImgEvent.LogImageEvent(id.String(), id.String(), "save")
+	}
+
+	if err := s.writeJSON(ocispec.ImageLayoutFile, ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion}); err != nil {
+		return err
+	}
+	if err := s.writeJSON(ociIndexFileName, index); err != nil {
+		return err
+	}
+	for _, dir := range []string{filepath.Join(ociBlobsDir, string(digest.Canonical)), ociBlobsDir} {
+		if err := system.Chtimes(filepath.Join(tempDir, dir), time.Unix(0, 0), time.Unix(0, 0)); err != nil {
+			return err
+		}
+	}
+
+	fs, err := archive.Tar(tempDir, archive.Uncompressed)
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	_, err = io.Copy(outStream, fs)
+	return err
+}
+
+// saveImage writes the config, layers and manifest blobs of an image, and
+// returns the descriptor of its manifest.
+func (s *ociSaveSession) saveImage(id image.ID) (ocispec.Descriptor, error) {
+	img := s.images[id].image
+	if len(img.RootFS.DiffIDs) == 0 {
+		return ocispec.Descriptor{}, fmt.Errorf("empty export - not implemented")
+	}
+
+	operatingSystem := img.OS
+	if operatingSystem == "" {
+		operatingSystem = runtime.GOOS
+	}
+
+	config, err := s.writeBlob(ocispec.MediaTypeImageConfig, img.RawJSON())
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	manifest := ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Config:    config,
+	}
+	for i := range img.RootFS.DiffIDs {
+		rootFS := *img.RootFS
+		rootFS.DiffIDs = rootFS.DiffIDs[:i+1]
+		desc, err := s.saveLayer(rootFS.ChainID(), operatingSystem)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		manifest.Layers = append(manifest.Layers, desc)
+	}
+
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc, err := s.writeBlob(ocispec.MediaTypeImageManifest, manifestJSON)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc.Platform = &ocispec.Platform{
+		Architecture: img.Architecture,
+		OS:           operatingSystem,
+		OSVersion:    img.OSVersion,
+		OSFeatures:   img.OSFeatures,
+	}
+	return desc, nil
+}
+
+// saveLayer writes the uncompressed tar of a layer as a blob, unless a
+// previous image already wrote it.
+func (s *ociSaveSession) saveLayer(id layer.ChainID, operatingSystem string) (ocispec.Descriptor, error) {
+	l, err := s.lss[operatingSystem].Get(id)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer layer.ReleaseAndLog(s.lss[operatingSystem], l)
+
+	dgst := digest.Digest(l.DiffID())
+	blobPath := s.blobPath(dgst)
+	if fi, err := os.Stat(blobPath); err == nil {
+		return ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: dgst, Size: fi.Size()}, nil
+	}
+
+	// Use system.CreateSequential rather than os.Create. This ensures sequential
+	// file access on Windows to avoid eating into MM standby list.
+	// On Linux, this equates to a regular os.Create.
+	tarFile, err := system.CreateSequential(blobPath)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer tarFile.Close()
+
+	arch, err := l.TarStream()
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer arch.Close()
+
+	digester := digest.Canonical.Digester()
+	size, err := io.Copy(io.MultiWriter(tarFile, digester.Hash()), arch)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if digester.Digest() != dgst {
+		return ocispec.Descriptor{}, errors.Errorf("invalid diffID for layer %s: got %s", dgst, digester.Digest())
+	}
+	if err := system.Chtimes(blobPath, time.Unix(0, 0), time.Unix(0, 0)); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: dgst, Size: size}, nil
+}
+
+func (s *ociSaveSession) writeBlob(mediaType string, data []byte) (ocispec.Descriptor, error) {
+	dgst := digest.FromBytes(data)
+	blobPath := s.blobPath(dgst)
+	if err := ioutil.WriteFile(blobPath, data, 0644); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if err := system.Chtimes(blobPath, time.Unix(0, 0), time.Unix(0, 0)); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return ocispec.Descriptor{MediaType: mediaType, Digest: dgst, Size: int64(len(data))}, nil
+}
+
+func (s *ociSaveSession) writeJSON(name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	p := filepath.Join(s.outDir, name)
+	if err := ioutil.WriteFile(p, data, 0644); err != nil {
+		return err
+	}
+	return system.Chtimes(p, time.Unix(0, 0), time.Unix(0, 0))
+}
+
+func (s *ociSaveSession) blobPath(dgst digest.Digest) string {
+	return filepath.Join(s.outDir, ociBlobsDir, string(dgst.Algorithm()), dgst.Hex())
+}
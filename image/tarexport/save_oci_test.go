@@ -0,0 +1,170 @@
+package tarexport // import "github.com/docker/docker/image/tarexport"
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/docker/distribution/reference"
+	_ "github.com/docker/docker/daemon/graphdriver/vfs"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/idtools"
+	refstore "github.com/docker/docker/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+type nopImageEventLogger struct{}
+
+func (nopImageEventLogger) LogImageEvent(imageID, refName, action string) {}
+
+type testStores struct {
+	is  image.Store
+	lss map[string]layer.Store
+	rs  refstore.Store
+}
+
+func newTestStores(t *testing.T) (testStores, func()) {
+	root, err := ioutil.TempDir("", "tarexport-")
+	assert.NilError(t, err)
+
+	ls, err := layer.NewStoreFromOptions(layer.StoreOptions{
+		Root:                      root,
+		MetadataStorePathTemplate: filepath.Join(root, "image", "%s", "layerdb"),
+		GraphDriver:               "vfs",
+		IDMapping:                 &idtools.IdentityMapping{},
+		OS:                        runtime.GOOS,
+	})
+	assert.NilError(t, err)
+	lss := map[string]layer.Store{runtime.GOOS: ls}
+
+	fs, err := image.NewFSStoreBackend(filepath.Join(root, "imagedb"))
+	assert.NilError(t, err)
+	imgStore, err := image.NewImageStore(fs, map[string]image.LayerGetReleaser{runtime.GOOS: ls})
+	assert.NilError(t, err)
+	rs, err := refstore.NewReferenceStore(filepath.Join(root, "repositories.json"))
+	assert.NilError(t, err)
+	return testStores{is: imgStore, lss: lss, rs: rs}, func() {
+		ls.Cleanup()
+		os.RemoveAll(root)
+	}
+}
+
+func createTestImage(t *testing.T, s testStores, ref string) image.ID {
+	tarStream, err := archive.Generate("hello", "world")
+	assert.NilError(t, err)
+	l, err := s.lss[runtime.GOOS].Register(tarStream, "")
+	assert.NilError(t, err)
+	defer s.lss[runtime.GOOS].Release(l)
+
+	config, err := json.Marshal(map[string]interface{}{
+		"architecture": runtime.GOARCH,
+		"os":           runtime.GOOS,
+		"rootfs":       map[string]interface{}{"type": "layers", "diff_ids": []string{l.DiffID().String()}},
+	})
+	assert.NilError(t, err)
+	id, err := s.is.Create(config)
+	assert.NilError(t, err)
+
+	named, err := reference.ParseNormalizedNamed(ref)
+	assert.NilError(t, err)
+	assert.NilError(t, s.rs.AddTag(named, id.Digest(), true))
+	return id
+}
+
+func readTarFiles(t *testing.T, data []byte) map[string][]byte {
+	files := make(map[string][]byte)
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		assert.NilError(t, err)
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		b, err := ioutil.ReadAll(tr)
+		assert.NilError(t, err)
+		files[hdr.Name] = b
+	}
+}
+
+func TestOCISaveLoad(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "vfs layer store is not used on Windows")
+	skip.If(t, os.Getuid() != 0, "skipping test that requires root")
+
+	src, cleanup := newTestStores(t)
+	defer cleanup()
+	id := createTestImage(t, src, "example.com/test:v1")
+
+	exporter := NewOCIExporter(src.is, src.lss, src.rs, nopImageEventLogger{})
+	var saved bytes.Buffer
+	assert.NilError(t, exporter.Save([]string{"example.com/test:v1"}, &saved))
+
+	// saving the same image again gives the same archive
+	var again bytes.Buffer
+	assert.NilError(t, exporter.Save([]string{"example.com/test:v1"}, &again))
+	assert.Check(t, bytes.Equal(saved.Bytes(), again.Bytes()))
+
+	files := readTarFiles(t, saved.Bytes())
+	assert.Check(t, is.Equal(string(files[ocispec.ImageLayoutFile]), `{"imageLayoutVersion":"1.0.0"}`))
+	var index ocispec.Index
+	assert.NilError(t, json.Unmarshal(files[ociIndexFileName], &index))
+	assert.Assert(t, is.Len(index.Manifests, 1))
+	assert.Check(t, is.Equal(index.Manifests[0].Annotations[ociImageNameAnnotation], "example.com/test:v1"))
+	assert.Check(t, is.Equal(index.Manifests[0].Annotations[ocispec.AnnotationRefName], "v1"))
+	assert.Check(t, is.Contains(files, "blobs/sha256/"+id.Digest().Hex()))
+	assert.Check(t, is.Contains(files, "blobs/sha256/"+index.Manifests[0].Digest.Hex()))
+
+	dst, cleanup := newTestStores(t)
+	defer cleanup()
+	importer := NewTarExporter(dst.is, dst.lss, dst.rs, nopImageEventLogger{})
+	var out bytes.Buffer
+	assert.NilError(t, importer.Load(ioutil.NopCloser(&saved), &out, true))
+	assert.Check(t, is.Contains(out.String(), "Loaded image: example.com/test:v1"))
+
+	named, err := reference.ParseNormalizedNamed("example.com/test:v1")
+	assert.NilError(t, err)
+	loaded, err := dst.rs.Get(named)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(loaded, id.Digest()))
+}
+
+func TestOCIImageName(t *testing.T) {
+	for _, tc := range []struct {
+		annotations map[string]string
+		expected    string
+	}{
+		{annotations: nil},
+		{annotations: map[string]string{ocispec.AnnotationRefName: "v1"}},
+		{annotations: map[string]string{ocispec.AnnotationRefName: "busybox:v1"}, expected: "docker.io/library/busybox:v1"},
+		{annotations: map[string]string{ocispec.AnnotationRefName: "example.com/test"}, expected: "example.com/test:latest"},
+		{
+			annotations: map[string]string{ocispec.AnnotationRefName: "v1", ociImageNameAnnotation: "docker.io/library/busybox:v1"},
+			expected:    "docker.io/library/busybox:v1",
+		},
+		{
+			annotations: map[string]string{ociImageNameAnnotation: "busybox@sha256:7a1b7f1f3a5d9fa0ecdab81d4186fd0ef3cb1ec8f71a1c3d6e5cd5ab3e68e5a6"},
+		},
+	} {
+		ref := ociImageName(tc.annotations)
+		if tc.expected == "" {
+			assert.Check(t, ref == nil, "%v", tc.annotations)
+			continue
+		}
+		if assert.Check(t, ref != nil, "%v", tc.annotations) {
+			assert.Check(t, is.Equal(ref.String(), tc.expected))
+		}
+	}
+}
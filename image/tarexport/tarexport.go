@@ -13,6 +13,20 @@ const (
 	legacyConfigFileName       = "json"
 	legacyVersionFileName      = "VERSION"
 	legacyRepositoriesFileName = "repositories"
+	ociIndexFileName           = "index.json"
+	ociBlobsDir                = "blobs"
+	// ociImageNameAnnotation is the annotation of the index descriptors
+	// holding the full reference of the images, as written by containerd.
+	ociImageNameAnnotation = "io.containerd.image.name"
+)
+
+// Formats of the archives written by Save.
+const (
+	// FormatDocker is the format of docker save, with a manifest.json and a
+	// legacy repositories file. It's the default format.
+	FormatDocker = "docker"
+	// FormatOCI is the OCI image layout.
+	FormatOCI = "oci"
 )
 
 type manifestItem struct {
@@ -28,6 +42,7 @@ type tarexporter struct {
 	lss            map[string]layer.Store
 	rs             refstore.Store
 	loggerImgEvent LogImageEvent
+	format         string
 }
 
 // LogImageEvent defines interface for event generation related to image tar(load and save) operations
@@ -43,5 +58,18 @@ func NewTarExporter(is image.Store, lss map[string]layer.Store, rs refstore.Stor
 		lss:            lss,
 		rs:             rs,
 		loggerImgEvent: loggerImgEvent,
+		format:         FormatDocker,
+	}
+}
+
+// NewOCIExporter returns a new Exporter saving images in the OCI image layout.
+// Its Load accepts the same archives as the one of NewTarExporter.
+func NewOCIExporter(is image.Store, lss map[string]layer.Store, rs refstore.Store, loggerImgEvent LogImageEvent) image.Exporter {
+	return &tarexporter{
+		is:             is,
+		lss:            lss,
+		rs:             rs,
+		loggerImgEvent: loggerImgEvent,
+		format:         FormatOCI,
 	}
 }
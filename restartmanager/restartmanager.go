@@ -13,6 +13,12 @@ const (
 	backoffMultiplier = 2
 	defaultTimeout    = 100 * time.Millisecond
 	maxRestartTimeout = 1 * time.Minute
+	// defaultResetWindow is how long a container has to run for the
+	// backoff to be reset back to its initial delay.
+	defaultResetWindow = 10 * time.Second
+
+	defaultCrashLoopThreshold = 5
+	defaultCrashLoopWindow    = 5 * time.Minute
 )
 
 // ErrRestartCanceled is returned when the restart manager has been
@@ -23,6 +29,8 @@ var ErrRestartCanceled = errors.New("restart canceled")
 type RestartManager interface {
 	Cancel() error
 	ShouldRestart(exitCode uint32, hasBeenManuallyStopped bool, executionDuration time.Duration) (bool, chan error, error)
+	CrashLooping() bool
+	RestartUnhealthy() bool
 }
 
 type restartManager struct {
@@ -34,6 +42,9 @@ type restartManager struct {
 	active       bool
 	cancel       chan struct{}
 	canceled     bool
+	restarts     []time.Time // restarts within the crash-loop window
+	crashLooping bool
+	unhealthy    bool // the next exit was caused by an unhealthy restart
 }
 
 // New returns a new restartManager based on a policy.
@@ -66,20 +77,10 @@ func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped
 	if rm.active {
 		return false, nil, fmt.Errorf("invalid call on an active restart manager")
 	}
-	// if the container ran for more than 10s, regardless of status and policy reset the
-	// the timeout back to the default.
-	if executionDuration.Seconds() >= 10 {
-		rm.timeout = 0
-	}
-	switch {
-	case rm.timeout == 0:
-		rm.timeout = defaultTimeout
-	case rm.timeout < maxRestartTimeout:
-		rm.timeout *= backoffMultiplier
-	}
-	if rm.timeout > maxRestartTimeout {
-		rm.timeout = maxRestartTimeout
-	}
+	rm.updateTimeout(executionDuration)
+
+	unhealthy := rm.unhealthy
+	rm.unhealthy = false
 
 	var restart bool
 	switch {
@@ -92,14 +93,21 @@ func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped
 		if max := rm.policy.MaximumRetryCount; max == 0 || rm.restartCount < max {
 			restart = exitCode != 0
 		}
+	case rm.policy.IsOnUnhealthy():
+		if max := rm.policy.MaximumRetryCount; max == 0 || rm.restartCount < max {
+			restart = exitCode != 0 || unhealthy
+		}
 	}
 
 	if !restart {
 		rm.active = false
+		rm.restarts = nil
+		rm.crashLooping = false
 		return false, nil, nil
 	}
 
 	rm.restartCount++
+	rm.recordRestart(time.Now())
 
 	unlockOnExit = false
 	rm.active = true
@@ -125,6 +133,87 @@ func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped
 	return true, ch, nil
 }
 
+// updateTimeout computes the delay before the next restart. If the container
+// ran for longer than the reset window, regardless of status and policy, the
+// delay is reset back to the initial delay.
+func (rm *restartManager) updateTimeout(executionDuration time.Duration) {
+	initialDelay, maxDelay := defaultTimeout, maxRestartTimeout
+	if rm.policy.InitialDelay > 0 {
+		initialDelay = rm.policy.InitialDelay
+	}
+	if rm.policy.MaxDelay > 0 {
+		maxDelay = rm.policy.MaxDelay
+	}
+	multiplier := float64(backoffMultiplier)
+	if rm.policy.Multiplier > 0 {
+		multiplier = rm.policy.Multiplier
+	}
+	resetWindow := defaultResetWindow
+	if rm.policy.ResetWindow > 0 {
+		resetWindow = rm.policy.ResetWindow
+	}
+
+	if executionDuration >= resetWindow {
+		rm.timeout = 0
+	}
+	switch {
+	case rm.timeout == 0:
+		rm.timeout = initialDelay
+	case rm.timeout < maxDelay:
+		rm.timeout = time.Duration(float64(rm.timeout) * multiplier)
+	}
+	if rm.timeout > maxDelay {
+		rm.timeout = maxDelay
+	}
+}
+
+// recordRestart adds a restart to the crash-loop window, drops restarts that
+// fell out of it, and updates the crash-looping state accordingly.
+func (rm *restartManager) recordRestart(now time.Time) {
+	threshold, window := defaultCrashLoopThreshold, defaultCrashLoopWindow
+	if rm.policy.CrashLoopThreshold > 0 {
+		threshold = rm.policy.CrashLoopThreshold
+	}
+	if rm.policy.CrashLoopWindow > 0 {
+		window = rm.policy.CrashLoopWindow
+	}
+
+	restarts := rm.restarts[:0]
+	for _, t := range rm.restarts {
+		if now.Sub(t) < window {
+			restarts = append(restarts, t)
+		}
+	}
+	restarts = append(restarts, now)
+	if len(restarts) > threshold {
+		restarts = restarts[len(restarts)-threshold:]
+	}
+	rm.restarts = restarts
+	rm.crashLooping = len(rm.restarts) >= threshold
+}
+
+// CrashLooping returns true if the container has been restarted more often
+// than the policy's crash-loop threshold within the crash-loop window.
+func (rm *restartManager) CrashLooping() bool {
+	rm.Lock()
+	defer rm.Unlock()
+	return rm.crashLooping
+}
+
+// RestartUnhealthy marks the next exit of the container as caused by an
+// unhealthy restart, so that it is restarted regardless of its exit code.
+// It returns false if the policy does not restart unhealthy containers, or
+// if such a restart is already pending.
+func (rm *restartManager) RestartUnhealthy() bool {
+	rm.Lock()
+	defer rm.Unlock()
+	if !rm.policy.IsOnUnhealthy() || rm.canceled || rm.active || rm.unhealthy {
+		return false
+	}
+	rm.unhealthy = true
+	return true
+}
+
 func (rm *restartManager) Cancel() error {
 	rm.Do(func() {
 		rm.Lock()
@@ -34,3 +34,88 @@ func TestRestartManagerTimeoutReset(t *testing.T) {
 		t.Fatalf("restart manager should have a timeout of 100 ms but has %s", rm.timeout)
 	}
 }
+
+func TestRestartManagerCustomBackoff(t *testing.T) {
+	policy := container.RestartPolicy{
+		Name:         "always",
+		InitialDelay: time.Second,
+		MaxDelay:     5 * time.Second,
+		Multiplier:   3,
+		ResetWindow:  time.Minute,
+	}
+	rm := New(policy, 0).(*restartManager)
+
+	for _, expected := range []time.Duration{time.Second, 3 * time.Second, 5 * time.Second, 5 * time.Second} {
+		rm.updateTimeout(30 * time.Second)
+		if rm.timeout != expected {
+			t.Fatalf("expected a timeout of %s, got %s", expected, rm.timeout)
+		}
+	}
+
+	rm.updateTimeout(time.Minute)
+	if rm.timeout != time.Second {
+		t.Fatalf("restart manager should have reset the timeout to 1s but has %s", rm.timeout)
+	}
+}
+
+func TestRestartManagerCrashLooping(t *testing.T) {
+	policy := container.RestartPolicy{
+		Name:               "always",
+		CrashLoopThreshold: 3,
+		CrashLoopWindow:    time.Minute,
+	}
+	rm := New(policy, 0).(*restartManager)
+
+	now := time.Now()
+	rm.recordRestart(now.Add(-2 * time.Minute))
+	rm.recordRestart(now.Add(-30 * time.Second))
+	rm.recordRestart(now.Add(-20 * time.Second))
+	if rm.CrashLooping() {
+		t.Fatal("restarts outside of the window should not count towards a crash loop")
+	}
+	rm.recordRestart(now)
+	if !rm.CrashLooping() {
+		t.Fatal("container should be crash-looping")
+	}
+	if len(rm.restarts) != policy.CrashLoopThreshold {
+		t.Fatalf("expected %d tracked restarts, got %d", policy.CrashLoopThreshold, len(rm.restarts))
+	}
+
+	rm.policy = container.RestartPolicy{Name: "on-failure"}
+	if should, _, err := rm.ShouldRestart(0, false, time.Second); err != nil || should {
+		t.Fatalf("container should not be restarted (err: %v)", err)
+	}
+	if rm.CrashLooping() {
+		t.Fatal("container should no longer be crash-looping once it is not restarted")
+	}
+}
+
+func TestRestartManagerOnUnhealthy(t *testing.T) {
+	rm := New(container.RestartPolicy{Name: "on-unhealthy"}, 0).(*restartManager)
+
+	should, _, err := rm.ShouldRestart(0, false, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if should {
+		t.Fatal("container exiting successfully should not be restarted")
+	}
+
+	if !rm.RestartUnhealthy() {
+		t.Fatal("expected an unhealthy restart to be scheduled")
+	}
+	if rm.RestartUnhealthy() {
+		t.Fatal("expected only one unhealthy restart to be scheduled at a time")
+	}
+	should, _, err = rm.ShouldRestart(0, false, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !should {
+		t.Fatal("unhealthy container should be restarted")
+	}
+
+	if New(container.RestartPolicy{Name: "always"}, 0).RestartUnhealthy() {
+		t.Fatal("unhealthy restarts should only be scheduled for the on-unhealthy policy")
+	}
+}
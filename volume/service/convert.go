@@ -92,9 +92,15 @@ func volumeToAPIType(v volume.Volume) types.Volume {
 	if cp, ok := v.(pathCacher); ok {
 		tv.Mountpoint = cp.CachedPath()
 	}
+	tv.Protected = isProtected(v)
 	return tv
 }
 
+func isProtected(v volume.Volume) bool {
+	pv, ok := v.(interface{ Protected() bool })
+	return ok && pv.Protected()
+}
+
 func filtersToBy(filter filters.Args, acceptedFilters map[string]bool) (By, error) {
 	if err := filter.Validate(acceptedFilters); err != nil {
 		return nil, err
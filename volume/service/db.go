@@ -16,6 +16,9 @@ type volumeMetadata struct {
 	Driver  string
 	Labels  map[string]string
 	Options map[string]string
+	// Protected volumes are skipped by prune, and can only be removed with
+	// force.
+	Protected bool `json:",omitempty"`
 }
 
 func (s *VolumeStore) setMeta(name string, meta volumeMetadata) error {
@@ -12,6 +12,8 @@ const (
 	errNoSuchVolume notFoundError = "no such volume"
 	// errNameConflict is a typed error returned on create when a volume exists with the given name, but for a different driver
 	errNameConflict conflictError = "volume name must be unique"
+	// errVolumeProtected is a typed error returned when trying to remove a protected volume without force
+	errVolumeProtected conflictError = "volume is protected, use force to remove it"
 )
 
 type conflictError string
@@ -70,6 +72,12 @@ func IsInUse(err error) bool {
 	return isErr(err, errVolumeInUse)
 }
 
+// IsProtected returns a boolean indicating whether the error indicates that a
+// volume is protected
+func IsProtected(err error) bool {
+	return isErr(err, errVolumeProtected)
+}
+
 // IsNotExist returns a boolean indicating whether the error indicates that the volume does not exist
 func IsNotExist(err error) bool {
 	return isErr(err, errNoSuchVolume)
@@ -74,6 +74,7 @@ func WithGetResolveStatus(cfg *GetConfig) {
 // RemoveConfig is used by `RemoveOption` to store config options for remove
 type RemoveConfig struct {
 	PurgeOnError bool
+	Force        bool
 }
 
 // RemoveOption is used to pass options to the volumes service `Remove` implementation
@@ -87,3 +88,11 @@ func WithPurgeOnError(b bool) RemoveOption {
 		o.PurgeOnError = b
 	}
 }
+
+// WithForce is an option passed to `Remove` which allows protected volumes
+// to be removed.
+func WithForce(b bool) RemoveOption {
+	return func(o *RemoveConfig) {
+		o.Force = b
+	}
+}
@@ -66,6 +66,9 @@ func (s *VolumeStore) restore() {
 			s.globalLock.Lock()
 			s.options[v.Name()] = meta.Options
 			s.labels[v.Name()] = meta.Labels
+			if meta.Protected {
+				s.protected[v.Name()] = true
+			}
 			s.names[v.Name()] = v
 			s.refs[v.Name()] = make(map[string]struct{})
 			s.globalLock.Unlock()
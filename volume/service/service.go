@@ -155,7 +155,7 @@ func (s *VolumesService) Remove(ctx context.Context, name string, rmOpts ...opts
 	err = s.vs.Remove(ctx, v, rmOpts...)
 	if IsNotExist(err) {
 		err = nil
-	} else if IsInUse(err) {
+	} else if IsInUse(err) || IsProtected(err) {
 		err = errdefs.Conflict(err)
 	} else if IsNotExist(err) && cfg.PurgeOnError {
 		err = nil
@@ -167,6 +167,23 @@ func (s *VolumesService) Remove(ctx context.Context, name string, rmOpts ...opts
 	return err
 }
 
+// SetProtected sets whether a volume is protected from prune and removal. A
+// protected volume can only be removed with force.
+func (s *VolumesService) SetProtected(ctx context.Context, name string, protected bool) error {
+	if err := s.vs.SetProtected(ctx, name, protected); err != nil {
+		if IsNotExist(err) {
+			err = errdefs.NotFound(err)
+		}
+		return err
+	}
+	action := "protect"
+	if !protected {
+		action = "unprotect"
+	}
+	s.eventLogger.LogVolumeEvent(name, action, map[string]string{})
+	return nil
+}
+
 var acceptedPruneFilters = map[string]bool{
 	"label":  true,
 	"label!": true,
@@ -209,7 +226,7 @@ func (s *VolumesService) Prune(ctx context.Context, filter filters.Args) (*types
 	}
 	ls, _, err := s.vs.Find(ctx, And(ByDriver(volume.DefaultDriverName), ByReferenced(false), by, CustomFilter(func(v volume.Volume) bool {
 		dv, ok := v.(volume.DetailedVolume)
-		return ok && len(dv.Options()) == 0
+		return ok && len(dv.Options()) == 0 && !isProtected(v)
 	})))
 	if err != nil {
 		return nil, err
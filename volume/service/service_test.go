@@ -233,6 +233,57 @@ func TestServicePrune(t *testing.T) {
 	assert.Assert(t, is.Equal(pr.VolumesDeleted[0], "test"))
 }
 
+func TestServiceProtect(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	assert.Assert(t, ds.Register(testutils.NewFakeDriver(volume.DefaultDriverName), volume.DefaultDriverName))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+	ctx := context.Background()
+
+	err := service.SetProtected(ctx, "notexist", true)
+	assert.Check(t, errdefs.IsNotFound(err), err)
+
+	_, err = service.Create(ctx, "test", volume.DefaultDriverName)
+	assert.NilError(t, err)
+	assert.NilError(t, service.SetProtected(ctx, "test", true))
+
+	v, err := service.Get(ctx, "test")
+	assert.NilError(t, err)
+	assert.Check(t, v.Protected)
+	ls, _, err := service.List(ctx, filters.NewArgs())
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(ls, 1))
+	assert.Check(t, ls[0].Protected)
+
+	pr, err := service.Prune(ctx, filters.NewArgs())
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(pr.VolumesDeleted, 0))
+
+	err = service.Remove(ctx, "test")
+	assert.Check(t, IsProtected(err), err)
+	assert.Check(t, errdefs.IsConflict(err), err)
+
+	assert.NilError(t, service.SetProtected(ctx, "test", false))
+	v, err = service.Get(ctx, "test")
+	assert.NilError(t, err)
+	assert.Check(t, !v.Protected)
+	assert.NilError(t, service.SetProtected(ctx, "test", true))
+
+	assert.NilError(t, service.Remove(ctx, "test", opts.WithForce(true)))
+	_, err = service.Get(ctx, "test")
+	assert.Check(t, IsNotExist(err), err)
+
+	// the protection is removed with the volume
+	_, err = service.Create(ctx, "test", volume.DefaultDriverName)
+	assert.NilError(t, err)
+	v, err = service.Get(ctx, "test")
+	assert.NilError(t, err)
+	assert.Check(t, !v.Protected)
+}
+
 func newTestService(t *testing.T, ds *volumedrivers.Store) (*VolumesService, func()) {
 	t.Helper()
 
@@ -28,9 +28,10 @@ const (
 
 type volumeWrapper struct {
 	volume.Volume
-	labels  map[string]string
-	scope   string
-	options map[string]string
+	labels    map[string]string
+	scope     string
+	options   map[string]string
+	protected bool
 }
 
 func (v volumeWrapper) Options() map[string]string {
@@ -60,6 +61,11 @@ func (v volumeWrapper) Scope() string {
 	return v.scope
 }
 
+// Protected returns whether the volume is protected from prune and removal
+func (v volumeWrapper) Protected() bool {
+	return v.protected
+}
+
 func (v volumeWrapper) CachedPath() string {
 	if vv, ok := v.Volume.(interface {
 		CachedPath() string
@@ -72,12 +78,13 @@ func (v volumeWrapper) CachedPath() string {
 // NewStore creates a new volume store at the given path
 func NewStore(rootPath string, drivers *drivers.Store) (*VolumeStore, error) {
 	vs := &VolumeStore{
-		locks:   &locker.Locker{},
-		names:   make(map[string]volume.Volume),
-		refs:    make(map[string]map[string]struct{}),
-		labels:  make(map[string]map[string]string),
-		options: make(map[string]map[string]string),
-		drivers: drivers,
+		locks:     &locker.Locker{},
+		names:     make(map[string]volume.Volume),
+		refs:      make(map[string]map[string]struct{}),
+		labels:    make(map[string]map[string]string),
+		options:   make(map[string]map[string]string),
+		protected: make(map[string]bool),
+		drivers:   drivers,
 	}
 
 	if rootPath != "" {
@@ -179,6 +186,7 @@ func (s *VolumeStore) purge(ctx context.Context, name string) error {
 	delete(s.refs, name)
 	delete(s.labels, name)
 	delete(s.options, name)
+	delete(s.protected, name)
 	return nil
 }
 
@@ -199,7 +207,9 @@ type VolumeStore struct {
 	labels map[string]map[string]string
 	// options stores volume options for each volume
 	options map[string]map[string]string
-	db      *bolt.DB
+	// protected stores whether each volume is protected
+	protected map[string]bool
+	db        *bolt.DB
 }
 
 func filterByDriver(names []string) filterFunc {
@@ -415,7 +425,7 @@ func (s *VolumeStore) list(ctx context.Context, driverNames ...string) ([]volume
 			}
 			for i, v := range vs {
 				s.globalLock.RLock()
-				vs[i] = volumeWrapper{v, s.labels[v.Name()], d.Scope(), s.options[v.Name()]}
+				vs[i] = volumeWrapper{v, s.labels[v.Name()], d.Scope(), s.options[v.Name()], s.protected[v.Name()]}
 				s.globalLock.RUnlock()
 			}
 
@@ -620,7 +630,7 @@ func (s *VolumeStore) create(ctx context.Context, name, driverName string, opts,
 	if err := s.setMeta(name, metadata); err != nil {
 		return nil, err
 	}
-	return volumeWrapper{v, labels, vd.Scope(), opts}, nil
+	return volumeWrapper{v, labels, vd.Scope(), opts, false}, nil
 }
 
 // Get looks if a volume with the given name exists and returns it if so
@@ -699,7 +709,7 @@ func (s *VolumeStore) getVolume(ctx context.Context, name, driverName string) (v
 		if err == nil {
 			scope = vd.Scope()
 		}
-		return volumeWrapper{vol, meta.Labels, scope, meta.Options}, nil
+		return volumeWrapper{vol, meta.Labels, scope, meta.Options, meta.Protected}, nil
 	}
 
 	logrus.Debugf("Probing all drivers for volume with name: %s", name)
@@ -722,7 +732,7 @@ func (s *VolumeStore) getVolume(ctx context.Context, name, driverName string) (v
 		if err := s.setMeta(name, meta); err != nil {
 			return nil, err
 		}
-		return volumeWrapper{v, meta.Labels, d.Scope(), meta.Options}, nil
+		return volumeWrapper{v, meta.Labels, d.Scope(), meta.Options, meta.Protected}, nil
 	}
 	return nil, errNoSuchVolume
 }
@@ -780,6 +790,10 @@ func (s *VolumeStore) Remove(ctx context.Context, v volume.Volume, rmOpts ...opt
 		return &OpErr{Err: errVolumeInUse, Name: name, Op: "remove", Refs: s.getRefs(name)}
 	}
 
+	if s.isProtected(name) && !cfg.Force {
+		return &OpErr{Err: errVolumeProtected, Name: name, Op: "remove"}
+	}
+
 	v, err := s.getVolume(ctx, name, v.DriverName())
 	if err != nil {
 		return err
@@ -806,6 +820,40 @@ func (s *VolumeStore) Remove(ctx context.Context, v volume.Volume, rmOpts ...opt
 	return err
 }
 
+// SetProtected sets whether the volume is protected from prune and removal.
+func (s *VolumeStore) SetProtected(ctx context.Context, name string, protected bool) error {
+	name = normalizeVolumeName(name)
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	if _, err := s.getVolume(ctx, name, ""); err != nil {
+		return &OpErr{Err: err, Name: name, Op: "protect"}
+	}
+	meta, err := s.getMeta(name)
+	if err != nil {
+		return &OpErr{Err: err, Name: name, Op: "protect"}
+	}
+	meta.Protected = protected
+	if err := s.setMeta(name, meta); err != nil {
+		return &OpErr{Err: err, Name: name, Op: "protect"}
+	}
+
+	s.globalLock.Lock()
+	if protected {
+		s.protected[name] = true
+	} else {
+		delete(s.protected, name)
+	}
+	s.globalLock.Unlock()
+	return nil
+}
+
+func (s *VolumeStore) isProtected(name string) bool {
+	s.globalLock.RLock()
+	defer s.globalLock.RUnlock()
+	return s.protected[name]
+}
+
 // Release releases the specified reference to the volume
 func (s *VolumeStore) Release(ctx context.Context, name string, ref string) error {
 	s.locks.Lock(name)